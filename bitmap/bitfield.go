@@ -0,0 +1,168 @@
+package bitmap
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// FieldType is the type of an integer field in a BitField, such as "i8" or "u16".
+type FieldType string
+
+// Signed returns the FieldType of a signed integer with the given number of bits, from
+// 1 to 64.
+func Signed(bits int) FieldType {
+	return FieldType("i" + strconv.Itoa(bits))
+}
+
+// Unsigned returns the FieldType of an unsigned integer with the given number of bits,
+// from 1 to 63.
+func Unsigned(bits int) FieldType {
+	return FieldType("u" + strconv.Itoa(bits))
+}
+
+// Offset is the offset of a field in a BitField.
+type Offset string
+
+// BitOffset returns the Offset of a field that starts at the given bit.
+func BitOffset(bit uint64) Offset {
+	return Offset(strconv.FormatUint(bit, 10))
+}
+
+// FieldOffset returns the Offset of the field with the given index, when the bitmap is
+// treated as an array of fields of the same type. For example, FieldOffset(2) of a
+// u8 field starts at bit 16.
+func FieldOffset(index uint64) Offset {
+	return Offset("#" + strconv.FormatUint(index, 10))
+}
+
+// Overflow is the behaviour of Set and IncrBy in a BitField when the value doesn't fit
+// in the field.
+type Overflow string
+
+const (
+	// OverflowWrap wraps the value around, like integer overflow in Go. It is the
+	// default.
+	OverflowWrap Overflow = "WRAP"
+
+	// OverflowSat saturates the value at the minimum or maximum of the field.
+	OverflowSat Overflow = "SAT"
+
+	// OverflowFail leaves the field unchanged, and the result of the operation is
+	// marked as Failed.
+	OverflowFail Overflow = "FAIL"
+)
+
+// FieldResult is the result of an operation in a BitField.
+type FieldResult struct {
+	// Value is the value read by Get, the old value for Set, and the new value for
+	// IncrBy.
+	Value int64
+
+	// Failed is set when the operation was not done because of OverflowFail.
+	Failed bool
+}
+
+// BitField builds a Redis BITFIELD command, which gets, sets and increments integer
+// fields of any width at any offset of a bitmap in a single call. The operations are
+// added with the chainable methods, and are sent in the same order by Exec.
+//
+// Exec doesn't clear the operations, so a BitField can be executed several times. A
+// BitField is not safe for concurrent use.
+type BitField struct {
+	provider redistypes.ConnProvider
+	ctx      context.Context
+	name     string
+	args     []interface{}
+	results  int
+	readOnly bool
+}
+
+// newBitField creates an empty BitField for the key name.
+func newBitField(ctx context.Context, p redistypes.ConnProvider, name string) *BitField {
+	return &BitField{
+		provider: p,
+		ctx:      ctx,
+		name:     name,
+		readOnly: true,
+	}
+}
+
+// Get adds a GET operation, which reads the field of type t at offset.
+func (b *BitField) Get(t FieldType, offset Offset) *BitField {
+	b.args = append(b.args, "GET", string(t), string(offset))
+	b.results++
+	return b
+}
+
+// Set adds a SET operation, which sets the field of type t at offset to value. Its
+// result is the old value.
+func (b *BitField) Set(t FieldType, offset Offset, value int64) *BitField {
+	b.args = append(b.args, "SET", string(t), string(offset), value)
+	b.results++
+	b.readOnly = false
+	return b
+}
+
+// IncrBy adds an INCRBY operation, which increments the field of type t at offset by
+// increment. Its result is the new value.
+func (b *BitField) IncrBy(t FieldType, offset Offset, increment int64) *BitField {
+	b.args = append(b.args, "INCRBY", string(t), string(offset), increment)
+	b.results++
+	b.readOnly = false
+	return b
+}
+
+// Overflow adds an OVERFLOW operation, which sets the overflow behaviour of the Set
+// and IncrBy operations that come after it.
+func (b *BitField) Overflow(overflow Overflow) *BitField {
+	b.args = append(b.args, "OVERFLOW", string(overflow))
+	b.readOnly = false
+	return b
+}
+
+// Exec implements the Redis command BITFIELD. It runs the operations and returns their
+// results, one for each Get, Set and IncrBy in the order they were added.
+//
+// See https://redis.io/commands/bitfield.
+func (b *BitField) Exec() ([]FieldResult, error) {
+	return b.exec("BITFIELD")
+}
+
+// ExecReadOnly implements the Redis command BITFIELD_RO. It works like Exec, but it can
+// be sent to a read-only replica. It only supports Get, so if any other operation was
+// added, an error is returned.
+//
+// See https://redis.io/commands/bitfield_ro.
+func (b *BitField) ExecReadOnly() ([]FieldResult, error) {
+	if !b.readOnly {
+		return nil, errors.New("Only Get can be used with BITFIELD_RO")
+	}
+	return b.exec("BITFIELD_RO")
+}
+
+func (b *BitField) exec(cmd string) ([]FieldResult, error) {
+	args := append([]interface{}{b.name}, b.args...)
+	values, err := redis.Values(internal.Do(b.ctx, b.provider, cmd, args...))
+	if err != nil {
+		return nil, err
+	} else if len(values) != b.results {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	results := make([]FieldResult, len(values))
+	for i, value := range values {
+		if value == nil {
+			results[i].Failed = true
+			continue
+		}
+		if results[i].Value, err = redis.Int64(value, nil); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,95 @@
+package bitmap_test
+
+import (
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldType(t *testing.T) {
+	assert.Equal(t, bitmap.FieldType("i8"), bitmap.Signed(8))
+	assert.Equal(t, bitmap.FieldType("u16"), bitmap.Unsigned(16))
+}
+
+func TestOffset(t *testing.T) {
+	assert.Equal(t, bitmap.Offset("100"), bitmap.BitOffset(100))
+	assert.Equal(t, bitmap.Offset("#2"), bitmap.FieldOffset(2))
+}
+
+func TestBitField_Exec(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	t.Run("empty", func(t *testing.T) {
+		results, err := b.BitField().Exec()
+		assert.Nil(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("get set incrby", func(t *testing.T) {
+		results, err := b.BitField().
+			Set(bitmap.Unsigned(8), bitmap.FieldOffset(1), 200).
+			IncrBy(bitmap.Signed(5), bitmap.BitOffset(100), 1).
+			Get(bitmap.Unsigned(4), bitmap.BitOffset(8)).
+			Exec()
+		assert.Nil(t, err)
+		assert.Equal(t, []bitmap.FieldResult{{Value: 0}, {Value: 1}, {Value: 12}}, results)
+
+		bytes, err := b.Bytes()
+		assert.Nil(t, err)
+		assert.Equal(t, byte(200), bytes[1])
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		results, err := b.BitField().
+			Set(bitmap.Signed(8), bitmap.FieldOffset(0), -1).
+			Get(bitmap.Unsigned(8), bitmap.FieldOffset(0)).
+			Get(bitmap.Signed(8), bitmap.FieldOffset(0)).
+			Exec()
+		assert.Nil(t, err)
+		assert.Equal(t, []bitmap.FieldResult{{Value: 0}, {Value: 255}, {Value: -1}}, results)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		field := b.BitField().
+			IncrBy(bitmap.Unsigned(2), bitmap.BitOffset(200), 1).
+			Overflow(bitmap.OverflowSat).
+			IncrBy(bitmap.Unsigned(2), bitmap.BitOffset(202), 1).
+			Overflow(bitmap.OverflowFail).
+			IncrBy(bitmap.Unsigned(2), bitmap.BitOffset(204), 1)
+
+		var results []bitmap.FieldResult
+		for i := 0; i < 4; i++ {
+			var err error
+			results, err = field.Exec()
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, []bitmap.FieldResult{{Value: 0}, {Value: 3}, {Failed: true}}, results)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := b.BitField().Get(bitmap.Unsigned(64), bitmap.BitOffset(0)).Exec()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestBitField_ExecReadOnly(t *testing.T) {
+	b := newBitmap(t, []byte{0x01, 0x02})
+	defer b.Base().Delete()
+
+	t.Run("get", func(t *testing.T) {
+		results, err := b.BitField().
+			Get(bitmap.Unsigned(8), bitmap.FieldOffset(0)).
+			Get(bitmap.Unsigned(8), bitmap.FieldOffset(1)).
+			ExecReadOnly()
+		assert.Nil(t, err)
+		assert.Equal(t, []bitmap.FieldResult{{Value: 1}, {Value: 2}}, results)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		_, err := b.BitField().Set(bitmap.Unsigned(8), bitmap.FieldOffset(0), 1).ExecReadOnly()
+		assert.NotNil(t, err)
+	})
+}
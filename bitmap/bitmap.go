@@ -0,0 +1,246 @@
+// Package bitmap contains a Go implementation of bitmaps in Redis, which are strings whose bits are
+// set and read individually. For more information about how the data type works, see the Redis
+// documentation.
+package bitmap
+
+import (
+	"context"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// Unit is the unit of the start and end offsets of a range in a bitmap.
+type Unit string
+
+const (
+	// Bytes makes the offsets byte indices. It is the default.
+	Bytes Unit = "BYTE"
+
+	// Bits makes the offsets bit indices. It requires Redis 7.0 or later.
+	Bits Unit = "BIT"
+)
+
+// Op is a bitwise operation for the Redis command BITOP.
+type Op string
+
+const (
+	// And stores the bits that are set in all of the bitmaps.
+	And Op = "AND"
+
+	// Or stores the bits that are set in any of the bitmaps.
+	Or Op = "OR"
+
+	// Xor stores the bits that are set in an odd number of the bitmaps.
+	Xor Op = "XOR"
+
+	// Not stores the inverse of a single bitmap.
+	Not Op = "NOT"
+
+	// Diff stores the bits that are set in the first bitmap but not in any of the
+	// others. It requires Redis 8.2 or later.
+	Diff Op = "DIFF"
+)
+
+// Bitmap is a Redis implementation of a bitmap. Offsets start at 0 with the most
+// significant bit of the first byte.
+type Bitmap interface {
+	// Base returns the base Type.
+	Base() redistypes.Type
+
+	// BitField returns a BitField for building a Redis BITFIELD command on the bitmap.
+	//
+	// See https://redis.io/commands/bitfield.
+	BitField() *BitField
+
+	// BitOp implements the Redis command BITOP. It applies op to the bitmap and others,
+	// in that order, and stores the result in dest, overwriting it. It returns the
+	// length of dest in bytes, which is the length of the longest bitmap. Not takes no
+	// others.
+	//
+	// See https://redis.io/commands/bitop.
+	BitOp(op Op, dest Bitmap, others ...Bitmap) (uint64, error)
+
+	// Bitset returns the whole bitmap as a Bitset using the Redis command GET. If the
+	// bitmap doesn't exist, the Bitset is empty.
+	//
+	// See https://redis.io/commands/get.
+	Bitset() (Bitset, error)
+
+	// Bytes returns the whole bitmap using the Redis command GET. If the bitmap doesn't
+	// exist, an empty slice is returned.
+	//
+	// See https://redis.io/commands/get.
+	Bytes() ([]byte, error)
+
+	// CloneTo copies the bitmap to a new key called name using the Redis command COPY,
+	// and returns a Bitmap for the copy. If name already exists, it is overwritten. If
+	// the bitmap does not exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (Bitmap, error)
+
+	// Count implements the Redis command BITCOUNT. It returns the number of bits that
+	// are set in the bitmap, or 0 if it doesn't exist.
+	//
+	// See https://redis.io/commands/bitcount.
+	Count() (uint64, error)
+
+	// CountRange implements the Redis command BITCOUNT with a range. It works like
+	// Count, but it only counts the bits from start to end, inclusive, in unit.
+	// Negative offsets start at the end of the bitmap. If unit is empty, Bytes is used.
+	//
+	// See https://redis.io/commands/bitcount.
+	CountRange(start, end int64, unit Unit) (uint64, error)
+
+	// GetBit implements the Redis command GETBIT. It returns whether the bit at offset
+	// is set. Bits past the end of the bitmap are not set.
+	//
+	// See https://redis.io/commands/getbit.
+	GetBit(offset uint64) (bool, error)
+
+	// Pos implements the Redis command BITPOS. It returns the offset of the first bit
+	// that is set if bit is true, or clear otherwise. If no bit is found, -1 is
+	// returned. Since the bits past the end of the bitmap are clear, a clear bit is
+	// always found unless the bitmap doesn't exist.
+	//
+	// See https://redis.io/commands/bitpos.
+	Pos(bit bool) (int64, error)
+
+	// PosRange implements the Redis command BITPOS with a range. It works like Pos, but
+	// it only looks at the bits from start to end, inclusive, in unit. The offset
+	// returned is still from the start of the bitmap. If unit is empty, Bytes is used.
+	//
+	// See https://redis.io/commands/bitpos.
+	PosRange(bit bool, start, end int64, unit Unit) (int64, error)
+
+	// SetBit implements the Redis command SETBIT. It sets or clears the bit at offset
+	// and returns whether it was set before. The bitmap grows as needed.
+	//
+	// See https://redis.io/commands/setbit.
+	SetBit(offset uint64, value bool) (bool, error)
+
+	// WithContext returns a copy of the Bitmap that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) Bitmap
+}
+
+type redisBitmap struct {
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
+}
+
+// NewRedisBitmap creates a Redis implementation of Bitmap given redigo connection conn and
+// name. The Redis key used to identify the Bitmap will be name.
+func NewRedisBitmap(conn redis.Conn, name string) Bitmap {
+	return NewRedisBitmapFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisBitmapFromProvider creates a Redis implementation of Bitmap given ConnProvider p and
+// name. Each command borrows a connection from p. The Redis key used to identify the Bitmap
+// will be name.
+func NewRedisBitmapFromProvider(p redistypes.ConnProvider, name string) Bitmap {
+	return &redisBitmap{
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
+	}
+}
+
+func (r redisBitmap) Base() redistypes.Type {
+	return r.base
+}
+
+func (r *redisBitmap) BitField() *BitField {
+	return newBitField(r.ctx, r.provider, r.Base().Name())
+}
+
+func (r *redisBitmap) BitOp(op Op, dest Bitmap, others ...Bitmap) (uint64, error) {
+	args := []interface{}{string(op), dest.Base().Name(), r.Base().Name()}
+	for _, other := range others {
+		args = append(args, other.Base().Name())
+	}
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "BITOP", args...))
+}
+
+func (r *redisBitmap) Bitset() (Bitset, error) {
+	return r.Bytes()
+}
+
+func (r *redisBitmap) Bytes() ([]byte, error) {
+	value, err := redis.Bytes(internal.Do(r.ctx, r.provider, "GET", r.Base().Name()))
+	if err == redis.ErrNil {
+		return []byte{}, nil
+	}
+	return value, err
+}
+
+func (r *redisBitmap) CloneTo(name string) (Bitmap, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisBitmapFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisBitmap) Count() (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "BITCOUNT", r.Base().Name()))
+}
+
+func (r *redisBitmap) CountRange(start, end int64, unit Unit) (uint64, error) {
+	args := append([]interface{}{r.Base().Name()}, rangeArgs(start, end, unit)...)
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "BITCOUNT", args...))
+}
+
+func (r *redisBitmap) GetBit(offset uint64) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "GETBIT", r.Base().Name(), offset))
+}
+
+func (r *redisBitmap) Pos(bit bool) (int64, error) {
+	return redis.Int64(internal.Do(r.ctx, r.provider, "BITPOS", r.Base().Name(), bitArg(bit)))
+}
+
+func (r *redisBitmap) PosRange(bit bool, start, end int64, unit Unit) (int64, error) {
+	args := append([]interface{}{r.Base().Name(), bitArg(bit)}, rangeArgs(start, end, unit)...)
+	return redis.Int64(internal.Do(r.ctx, r.provider, "BITPOS", args...))
+}
+
+func (r *redisBitmap) SetBit(offset uint64, value bool) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "SETBIT", r.Base().Name(), offset, bitArg(value)))
+}
+
+func (r *redisBitmap) WithContext(ctx context.Context) Bitmap {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisBitmap{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// bitArg returns the argument for a bit value.
+func bitArg(bit bool) int {
+	if bit {
+		return 1
+	}
+	return 0
+}
+
+// rangeArgs returns the arguments for a range of a bitmap. The unit is only sent if it
+// is set, so the default works with versions of Redis before 7.0.
+func rangeArgs(start, end int64, unit Unit) []interface{} {
+	args := []interface{}{start, end}
+	if unit != "" {
+		args = append(args, string(unit))
+	}
+	return args
+}
@@ -0,0 +1,330 @@
+package bitmap_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func ExampleNewRedisBitmap() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	active := bitmap.NewRedisBitmap(conn, "my_active_users")
+	defer active.Base().Delete()
+
+	for _, user := range []uint64{3, 10, 42} {
+		_, _ = active.SetBit(user, true)
+	}
+
+	count, _ := active.Count()
+	fmt.Println(count)
+
+	bits, _ := active.Bitset()
+	fmt.Println(bits.Offsets())
+
+	// Output:
+	// 3
+	// [3 10 42]
+}
+
+// newBitmap returns a bitmap holding value.
+func newBitmap(t *testing.T, value []byte) bitmap.Bitmap {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	_, err := conn.Do("SET", b.Base().Name(), value)
+	assert.Nil(t, err)
+	return b
+}
+
+func TestNewRedisBitmapFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	b := bitmap.NewRedisBitmapFromProvider(pool, test.RandomKey())
+	defer b.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := b.SetBit(uint64(i), true)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	value, err := b.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
+func TestRedisBitmap_BitOp(t *testing.T) {
+	a := newBitmap(t, []byte{0xf0})
+	defer a.Base().Delete()
+	b := newBitmap(t, []byte{0x3c, 0xff})
+	defer b.Base().Delete()
+
+	dest := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer dest.Base().Delete()
+
+	tests := []struct {
+		name     string
+		op       bitmap.Op
+		others   []bitmap.Bitmap
+		expected []byte
+	}{
+		{name: "and", op: bitmap.And, others: []bitmap.Bitmap{b}, expected: []byte{0x30, 0x00}},
+		{name: "or", op: bitmap.Or, others: []bitmap.Bitmap{b}, expected: []byte{0xfc, 0xff}},
+		{name: "xor", op: bitmap.Xor, others: []bitmap.Bitmap{b}, expected: []byte{0xcc, 0xff}},
+		{name: "not", op: bitmap.Not, expected: []byte{0x0f}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			length, err := a.BitOp(test.op, dest, test.others...)
+			assert.Nil(t, err)
+			assert.EqualValues(t, len(test.expected), length)
+
+			value, err := dest.Bytes()
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+
+	t.Run("not with others", func(t *testing.T) {
+		_, err := a.BitOp(bitmap.Not, dest, b)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRedisBitmap_Bitset(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := b.Bitset()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value.Len())
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = b.SetBit(1, true)
+		_, _ = b.SetBit(9, true)
+
+		value, err := b.Bitset()
+		assert.Nil(t, err)
+		assert.Equal(t, bitmap.Bitset{0x40, 0x40}, value)
+	})
+}
+
+func TestRedisBitmap_Bytes(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := b.Bytes()
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{}, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = b.SetBit(7, true)
+
+		value, err := b.Bytes()
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x01}, value)
+	})
+}
+
+func TestRedisBitmap_CloneTo(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := b.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = b.SetBit(0, true)
+
+		clone, err := b.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = b.SetBit(1, true)
+
+		value, err := clone.Count()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+}
+
+func TestRedisBitmap_Count(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	value, err := b.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	_, _ = conn.Do("SET", b.Base().Name(), "foobar")
+
+	value, err = b.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 26, value)
+}
+
+func TestRedisBitmap_CountRange(t *testing.T) {
+	b := newBitmap(t, []byte("foobar"))
+	defer b.Base().Delete()
+
+	t.Run("bytes", func(t *testing.T) {
+		value, err := b.CountRange(1, 1, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 6, value)
+
+		value, err = b.CountRange(1, 1, bitmap.Bytes)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 6, value)
+	})
+
+	t.Run("bits", func(t *testing.T) {
+		value, err := b.CountRange(5, 30, bitmap.Bits)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 17, value)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		value, err := b.CountRange(-1, -1, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 4, value)
+	})
+}
+
+func TestRedisBitmap_GetBit(t *testing.T) {
+	b := newBitmap(t, []byte{0x40})
+	defer b.Base().Delete()
+
+	value, err := b.GetBit(1)
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = b.GetBit(0)
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	value, err = b.GetBit(100)
+	assert.Nil(t, err)
+	assert.False(t, value)
+}
+
+func TestRedisBitmap_Pos(t *testing.T) {
+	t.Run("non-existing key", func(t *testing.T) {
+		b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+
+		value, err := b.Pos(true)
+		assert.Nil(t, err)
+		assert.EqualValues(t, -1, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		b := newBitmap(t, []byte{0xff, 0xf0, 0x00})
+		defer b.Base().Delete()
+
+		value, err := b.Pos(false)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 12, value)
+
+		value, err = b.Pos(true)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value)
+	})
+}
+
+func TestRedisBitmap_PosRange(t *testing.T) {
+	b := newBitmap(t, []byte{0x00, 0xff, 0xf0})
+	defer b.Base().Delete()
+
+	t.Run("bytes", func(t *testing.T) {
+		value, err := b.PosRange(true, 2, -1, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 16, value)
+	})
+
+	t.Run("bits", func(t *testing.T) {
+		value, err := b.PosRange(true, 7, 15, bitmap.Bits)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 8, value)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		value, err := b.PosRange(true, 0, 0, bitmap.Bytes)
+		assert.Nil(t, err)
+		assert.EqualValues(t, -1, value)
+	})
+}
+
+func TestRedisBitmap_SetBit(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	value, err := b.SetBit(7, true)
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	value, err = b.SetBit(7, false)
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	bytes, err := b.Bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x00}, bytes)
+}
+
+func TestRedisBitmap_WithContext(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := b.WithContext(ctx).SetBit(0, true)
+	assert.Equal(t, context.Canceled, err)
+
+	value, err := b.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
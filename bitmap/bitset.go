@@ -0,0 +1,44 @@
+package bitmap
+
+// Bitset is a bitmap read into memory. The bits are in the same order as in Redis,
+// so offset 0 is the most significant bit of the first byte.
+type Bitset []byte
+
+// Len returns the number of bits in the Bitset.
+func (b Bitset) Len() uint64 {
+	return uint64(len(b)) * 8
+}
+
+// Get returns whether the bit at offset is set. Bits past the end of the Bitset are
+// not set.
+func (b Bitset) Get(offset uint64) bool {
+	if offset >= b.Len() {
+		return false
+	}
+	return b[offset/8]&(0x80>>(offset%8)) != 0
+}
+
+// Count returns the number of bits that are set, like Bitmap.Count.
+func (b Bitset) Count() uint64 {
+	var count uint64
+	for _, c := range b {
+		// Clear the lowest set bit until none are left.
+		for ; c != 0; c &= c - 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// Offsets returns the offsets of the bits that are set, in increasing order.
+func (b Bitset) Offsets() []uint64 {
+	offsets := make([]uint64, 0, b.Count())
+	for i, c := range b {
+		for bit := uint64(0); c != 0; bit, c = bit+1, c<<1 {
+			if c&0x80 != 0 {
+				offsets = append(offsets, uint64(i)*8+bit)
+			}
+		}
+	}
+	return offsets
+}
@@ -0,0 +1,31 @@
+package bitmap_test
+
+import (
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitset(t *testing.T) {
+	b := bitmap.Bitset{0x81, 0x00, 0x10}
+
+	assert.EqualValues(t, 24, b.Len())
+	assert.EqualValues(t, 3, b.Count())
+	assert.Equal(t, []uint64{0, 7, 19}, b.Offsets())
+
+	assert.True(t, b.Get(0))
+	assert.False(t, b.Get(1))
+	assert.True(t, b.Get(7))
+	assert.True(t, b.Get(19))
+	assert.False(t, b.Get(24))
+}
+
+func TestBitset_Empty(t *testing.T) {
+	var b bitmap.Bitset
+
+	assert.EqualValues(t, 0, b.Len())
+	assert.EqualValues(t, 0, b.Count())
+	assert.Empty(t, b.Offsets())
+	assert.False(t, b.Get(0))
+}
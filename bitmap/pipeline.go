@@ -0,0 +1,51 @@
+package bitmap
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+)
+
+// Pipelined is a Bitmap whose commands are queued in a redistypes.Batch instead of being sent
+// immediately. Each method returns a future that is resolved when the Batch is executed. The
+// methods work like the Bitmap methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Count queues the Redis command BITCOUNT. See Bitmap.Count.
+	Count() redistypes.Uint64Future
+
+	// GetBit queues the Redis command GETBIT. See Bitmap.GetBit.
+	GetBit(offset uint64) redistypes.BoolFuture
+
+	// SetBit queues the Redis command SETBIT. See Bitmap.SetBit.
+	SetBit(offset uint64, value bool) redistypes.BoolFuture
+}
+
+type pipelinedBitmap struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined bitmap that queues the commands of bm in b.
+func NewPipelined(b redistypes.Batch, bm Bitmap) Pipelined {
+	return &pipelinedBitmap{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, bm.Base()),
+	}
+}
+
+func (r pipelinedBitmap) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedBitmap) Count() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("BITCOUNT", r.base.Name())}
+}
+
+func (r *pipelinedBitmap) GetBit(offset uint64) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("GETBIT", r.base.Name(), offset)}
+}
+
+func (r *pipelinedBitmap) SetBit(offset uint64, value bool) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("SETBIT", r.base.Name(), offset, bitArg(value))}
+}
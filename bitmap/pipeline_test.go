@@ -0,0 +1,49 @@
+package bitmap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	b := bitmap.NewRedisBitmap(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	pb := bitmap.NewPipelined(p, b)
+	assert.Equal(t, b.Base().Name(), pb.Base().Name())
+
+	set := pb.SetBit(3, true)
+	again := pb.SetBit(3, true)
+	get := pb.GetBit(3)
+	unset := pb.GetBit(4)
+	count := pb.Count()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	value, err := set.Result()
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	value, err = again.Result()
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = get.Result()
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = unset.Result()
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	n, err := count.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, n)
+}
@@ -0,0 +1,458 @@
+// Package geo contains a Go implementation of the geospatial index in Redis, which stores members
+// with their longitude and latitude in a sorted set. For more information about how the data
+// structure works, see the Redis documentation.
+package geo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrMemberNotFound is returned when a member that doesn't exist in a geospatial index is
+// looked up.
+var ErrMemberNotFound = errors.New("Member does not exist")
+
+// Position is a longitude and latitude in degrees.
+type Position struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// Location is a member of a geospatial index with its position.
+type Location struct {
+	Member string
+	Position
+}
+
+// Unit is the unit of a distance.
+type Unit string
+
+const (
+	// Meters is the default unit.
+	Meters Unit = "m"
+
+	// Kilometers is a unit of 1000 meters.
+	Kilometers Unit = "km"
+
+	// Miles is a unit of about 1609 meters.
+	Miles Unit = "mi"
+
+	// Feet is a unit of about 0.3 meters.
+	Feet Unit = "ft"
+)
+
+// AddOption is an option for the Redis command GEOADD.
+type AddOption string
+
+const (
+	// AddNX only adds new members, and doesn't update the ones that already exist.
+	AddNX AddOption = "NX"
+
+	// AddXX only updates members that already exist, and doesn't add new ones.
+	AddXX AddOption = "XX"
+
+	// AddCH makes Add return the number of members that were added or whose position
+	// changed, instead of only the number added.
+	AddCH AddOption = "CH"
+)
+
+// Order is the order of the results of a search.
+type Order string
+
+const (
+	// Unordered returns the results in no particular order. It is the default.
+	Unordered Order = ""
+
+	// Ascending returns the results from the nearest to the farthest.
+	Ascending Order = "ASC"
+
+	// Descending returns the results from the farthest to the nearest.
+	Descending Order = "DESC"
+)
+
+// SearchQuery is the area searched by the Redis commands GEOSEARCH and GEOSEARCHSTORE.
+type SearchQuery struct {
+	// FromMember is the member at the center of the area. If it is empty, FromPosition
+	// is used instead.
+	FromMember string
+
+	// FromPosition is the center of the area if FromMember is empty.
+	FromPosition Position
+
+	// Radius searches a circle with the given radius. If it is 0, a box of Width and
+	// Height is searched instead.
+	Radius float64
+
+	// Width and Height are the size of the box searched if Radius is 0.
+	Width  float64
+	Height float64
+
+	// Unit is the unit of Radius, Width and Height, and of the distances returned. If it
+	// is empty, Meters is used.
+	Unit Unit
+}
+
+// SearchOptions are the options for the Redis commands GEOSEARCH and GEOSEARCHSTORE.
+type SearchOptions struct {
+	// Order is the order of the results.
+	Order Order
+
+	// Count limits the number of results, or there is no limit if it is 0. Unless Any is
+	// set, the nearest results are returned.
+	Count int64
+
+	// Any returns as soon as Count results are found, so they may not be the nearest.
+	// It is faster, and is only used if Count is set.
+	Any bool
+
+	// WithCoord, WithDist and WithHash fill in the Position, Distance and Hash of each
+	// SearchResult. They aren't used by SearchStore.
+	WithCoord bool
+	WithDist  bool
+	WithHash  bool
+
+	// StoreDist makes SearchStore store the distance of each member as its score,
+	// instead of its position. The result is then a plain sorted set. It isn't used by
+	// Search.
+	StoreDist bool
+}
+
+// SearchResult is a member found by a search. The other fields are set depending on
+// SearchOptions.
+type SearchResult struct {
+	Member string
+
+	// Position is set with WithCoord.
+	Position Position
+
+	// Distance is the distance from the center of the search, in the unit of the
+	// SearchQuery. It is set with WithDist.
+	Distance float64
+
+	// Hash is the raw geohash of the member, which is its score in the sorted set. It
+	// is set with WithHash.
+	Hash int64
+}
+
+// Geo is a Redis implementation of a geospatial index.
+type Geo interface {
+	// Base returns the base Type.
+	Base() redistypes.Type
+
+	// Add implements the Redis command GEOADD. It adds locations to the geospatial
+	// index, or updates their positions if they already exist, and returns the number
+	// of members added. The behaviour can be changed with options, as described in the
+	// Redis documentation.
+	//
+	// See https://redis.io/commands/geoadd.
+	Add(locations []Location, options ...AddOption) (uint64, error)
+
+	// CloneTo copies the geospatial index to a new key called name using the Redis
+	// command COPY, and returns a Geo for the copy. If name already exists, it is
+	// overwritten. If the geospatial index does not exist, redistypes.ErrKeyNotFound is
+	// returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (Geo, error)
+
+	// Dist implements the Redis command GEODIST. It returns the distance between
+	// member1 and member2 in unit, or in meters if unit is empty. If either member
+	// doesn't exist, ErrMemberNotFound is returned.
+	//
+	// See https://redis.io/commands/geodist.
+	Dist(member1, member2 string, unit Unit) (float64, error)
+
+	// Hash implements the Redis command GEOHASH. It returns the geohash strings of
+	// members, in the same order. The hash of a member that doesn't exist is empty.
+	//
+	// See https://redis.io/commands/geohash.
+	Hash(members ...string) ([]string, error)
+
+	// Pos implements the Redis command GEOPOS. It returns the positions of members.
+	// Members that don't exist are left out of the map.
+	//
+	// See https://redis.io/commands/geopos.
+	Pos(members ...string) (map[string]Position, error)
+
+	// Remove implements the Redis command ZREM. It removes members from the geospatial
+	// index and returns the number removed, not including the ones that didn't exist.
+	//
+	// See https://redis.io/commands/zrem.
+	Remove(members ...string) (uint64, error)
+
+	// Search implements the Redis command GEOSEARCH. It returns the members in the area
+	// described by query, according to options. If query.FromMember doesn't exist, an
+	// error is returned.
+	//
+	// See https://redis.io/commands/geosearch.
+	Search(query SearchQuery, options SearchOptions) ([]SearchResult, error)
+
+	// SearchStore implements the Redis command GEOSEARCHSTORE. It works like Search, but
+	// it stores the members found in a new geospatial index with the given name, and
+	// returns it. If name already exists, it is overwritten.
+	//
+	// See https://redis.io/commands/geosearchstore.
+	SearchStore(name string, query SearchQuery, options SearchOptions) (Geo, error)
+
+	// WithContext returns a copy of the Geo that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) Geo
+}
+
+type redisGeo struct {
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
+}
+
+// NewRedisGeo creates a Redis implementation of Geo given redigo connection conn and name. The
+// Redis key used to identify the Geo will be name.
+func NewRedisGeo(conn redis.Conn, name string) Geo {
+	return NewRedisGeoFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisGeoFromProvider creates a Redis implementation of Geo given ConnProvider p and name.
+// Each command borrows a connection from p. The Redis key used to identify the Geo will be name.
+func NewRedisGeoFromProvider(p redistypes.ConnProvider, name string) Geo {
+	return &redisGeo{
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
+	}
+}
+
+func (r redisGeo) Base() redistypes.Type {
+	return r.base
+}
+
+func (r *redisGeo) Add(locations []Location, options ...AddOption) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "GEOADD", addArgs(r.Base().Name(), locations, options)...))
+}
+
+func (r *redisGeo) CloneTo(name string) (Geo, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisGeoFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisGeo) Dist(member1, member2 string, unit Unit) (float64, error) {
+	args := []interface{}{r.Base().Name(), member1, member2}
+	if unit != "" {
+		args = append(args, string(unit))
+	}
+
+	dist, err := redis.Float64(internal.Do(r.ctx, r.provider, "GEODIST", args...))
+	if err == redis.ErrNil {
+		return 0, ErrMemberNotFound
+	}
+	return dist, err
+}
+
+func (r *redisGeo) Hash(members ...string) ([]string, error) {
+	hashes, err := redis.Strings(internal.Do(r.ctx, r.provider, "GEOHASH", memberArgs(r.Base().Name(), members)...))
+	if err != nil {
+		return nil, err
+	} else if len(hashes) != len(members) {
+		return nil, errors.New("Unexpected response length")
+	}
+	return hashes, nil
+}
+
+func (r *redisGeo) Pos(members ...string) (map[string]Position, error) {
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "GEOPOS", memberArgs(r.Base().Name(), members)...))
+	if err != nil {
+		return nil, err
+	} else if len(values) != len(members) {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	positions := make(map[string]Position, len(members))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		if positions[members[i]], err = position(value); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
+
+func (r *redisGeo) Remove(members ...string) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZREM", memberArgs(r.Base().Name(), members)...))
+}
+
+func (r *redisGeo) Search(query SearchQuery, options SearchOptions) ([]SearchResult, error) {
+	args := append([]interface{}{r.Base().Name()}, searchArgs(query, options)...)
+	if options.WithCoord {
+		args = append(args, "WITHCOORD")
+	}
+	if options.WithDist {
+		args = append(args, "WITHDIST")
+	}
+	if options.WithHash {
+		args = append(args, "WITHHASH")
+	}
+
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "GEOSEARCH", args...))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, len(values))
+	for i, value := range values {
+		if results[i], err = searchResult(value, options); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (r *redisGeo) SearchStore(name string, query SearchQuery, options SearchOptions) (Geo, error) {
+	args := append([]interface{}{name, r.Base().Name()}, searchArgs(query, options)...)
+	if options.StoreDist {
+		args = append(args, "STOREDIST")
+	}
+
+	if _, err := internal.Do(r.ctx, r.provider, "GEOSEARCHSTORE", args...); err != nil {
+		return nil, err
+	}
+
+	return NewRedisGeoFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisGeo) WithContext(ctx context.Context) Geo {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisGeo{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// addArgs returns the arguments to GEOADD for locations with options.
+func addArgs(name string, locations []Location, options []AddOption) []interface{} {
+	args := make([]interface{}, 0, 1+len(options)+3*len(locations))
+	args = append(args, name)
+	for _, option := range options {
+		args = append(args, string(option))
+	}
+	for _, location := range locations {
+		args = append(args, location.Longitude, location.Latitude, location.Member)
+	}
+	return args
+}
+
+// memberArgs returns the arguments to a command that takes name followed by members.
+func memberArgs(name string, members []string) []interface{} {
+	args := make([]interface{}, 0, 1+len(members))
+	args = append(args, name)
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return args
+}
+
+// searchArgs returns the arguments to GEOSEARCH and GEOSEARCHSTORE after the keys, not
+// including the options that only one of them has.
+func searchArgs(query SearchQuery, options SearchOptions) []interface{} {
+	var args []interface{}
+	if query.FromMember != "" {
+		args = append(args, "FROMMEMBER", query.FromMember)
+	} else {
+		args = append(args, "FROMLONLAT", query.FromPosition.Longitude, query.FromPosition.Latitude)
+	}
+
+	unit := query.Unit
+	if unit == "" {
+		unit = Meters
+	}
+	if query.Radius != 0 {
+		args = append(args, "BYRADIUS", query.Radius, string(unit))
+	} else {
+		args = append(args, "BYBOX", query.Width, query.Height, string(unit))
+	}
+
+	if options.Order != Unordered {
+		args = append(args, string(options.Order))
+	}
+	if options.Count > 0 {
+		args = append(args, "COUNT", options.Count)
+		if options.Any {
+			args = append(args, "ANY")
+		}
+	}
+	return args
+}
+
+// searchResult converts a single result of GEOSEARCH. The fields that come after the
+// member are in the order distance, hash and coordinates.
+func searchResult(reply interface{}, options SearchOptions) (SearchResult, error) {
+	if !options.WithCoord && !options.WithDist && !options.WithHash {
+		member, err := redis.String(reply, nil)
+		return SearchResult{Member: member}, err
+	}
+
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	n := 1
+	for _, with := range []bool{options.WithDist, options.WithHash, options.WithCoord} {
+		if with {
+			n++
+		}
+	}
+	if len(values) != n {
+		return SearchResult{}, errors.New("Unexpected response length")
+	}
+
+	var result SearchResult
+	if result.Member, err = redis.String(values[0], nil); err != nil {
+		return SearchResult{}, err
+	}
+	values = values[1:]
+	if options.WithDist {
+		if result.Distance, err = redis.Float64(values[0], nil); err != nil {
+			return SearchResult{}, err
+		}
+		values = values[1:]
+	}
+	if options.WithHash {
+		if result.Hash, err = redis.Int64(values[0], nil); err != nil {
+			return SearchResult{}, err
+		}
+		values = values[1:]
+	}
+	if options.WithCoord {
+		if result.Position, err = position(values[0]); err != nil {
+			return SearchResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// position converts a reply holding a longitude and latitude.
+func position(reply interface{}) (Position, error) {
+	coords, err := redis.Float64s(reply, nil)
+	if err != nil {
+		return Position{}, err
+	} else if len(coords) != 2 {
+		return Position{}, errors.New("Unexpected response length")
+	}
+	return Position{Longitude: coords[0], Latitude: coords[1]}, nil
+}
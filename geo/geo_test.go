@@ -0,0 +1,338 @@
+package geo_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/geo"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+var (
+	palermo = geo.Location{Member: "Palermo", Position: geo.Position{Longitude: 13.361389, Latitude: 38.115556}}
+	catania = geo.Location{Member: "Catania", Position: geo.Position{Longitude: 15.087269, Latitude: 37.502669}}
+)
+
+func ExampleNewRedisGeo() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	stores := geo.NewRedisGeo(conn, "my_stores")
+	defer stores.Base().Delete()
+
+	_, _ = stores.Add([]geo.Location{
+		{Member: "Palermo", Position: geo.Position{Longitude: 13.361389, Latitude: 38.115556}},
+		{Member: "Catania", Position: geo.Position{Longitude: 15.087269, Latitude: 37.502669}},
+	})
+
+	results, _ := stores.Search(geo.SearchQuery{
+		FromPosition: geo.Position{Longitude: 15, Latitude: 37},
+		Radius:       200,
+		Unit:         geo.Kilometers,
+	}, geo.SearchOptions{Order: geo.Ascending, WithDist: true})
+	for _, result := range results {
+		fmt.Printf("%v %.1f\n", result.Member, result.Distance)
+	}
+
+	// Output:
+	// Catania 56.4
+	// Palermo 190.4
+}
+
+// newGeo returns a geospatial index with palermo and catania.
+func newGeo(t *testing.T) geo.Geo {
+	g := geo.NewRedisGeo(conn, test.RandomKey())
+	_, err := g.Add([]geo.Location{palermo, catania})
+	assert.Nil(t, err)
+	return g
+}
+
+// resultMembers returns the members of results.
+func resultMembers(results []geo.SearchResult) []string {
+	members := make([]string, len(results))
+	for i, result := range results {
+		members[i] = result.Member
+	}
+	return members
+}
+
+func TestNewRedisGeoFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	g := geo.NewRedisGeoFromProvider(pool, test.RandomKey())
+	defer g.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := g.Add([]geo.Location{{Member: fmt.Sprint(i), Position: geo.Position{Longitude: float64(i)}}})
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	positions, err := g.Pos("0", "9")
+	assert.Nil(t, err)
+	assert.Len(t, positions, 2)
+}
+
+func TestRedisGeo_Add(t *testing.T) {
+	g := geo.NewRedisGeo(conn, test.RandomKey())
+	defer g.Base().Delete()
+
+	moved := geo.Location{Member: "Palermo", Position: catania.Position}
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := g.Add([]geo.Location{palermo})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+
+	t.Run("NX", func(t *testing.T) {
+		value, err := g.Add([]geo.Location{moved, catania}, geo.AddNX)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+
+	t.Run("XX", func(t *testing.T) {
+		value, err := g.Add([]geo.Location{{Member: "Rome", Position: palermo.Position}}, geo.AddXX)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value)
+	})
+
+	t.Run("CH", func(t *testing.T) {
+		value, err := g.Add([]geo.Location{moved, catania}, geo.AddXX, geo.AddCH)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+
+		dist, err := g.Dist("Palermo", "Catania", "")
+		assert.Nil(t, err)
+		assert.Equal(t, 0.0, dist)
+	})
+
+	t.Run("invalid position", func(t *testing.T) {
+		_, err := g.Add([]geo.Location{{Member: "a", Position: geo.Position{Latitude: 90}}})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRedisGeo_CloneTo(t *testing.T) {
+	g := geo.NewRedisGeo(conn, test.RandomKey())
+	defer g.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := g.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = g.Add([]geo.Location{palermo})
+
+		clone, err := g.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = g.Remove("Palermo")
+
+		positions, err := clone.Pos("Palermo")
+		assert.Nil(t, err)
+		assert.Len(t, positions, 1)
+	})
+}
+
+func TestRedisGeo_Dist(t *testing.T) {
+	g := newGeo(t)
+	defer g.Base().Delete()
+
+	t.Run("meters", func(t *testing.T) {
+		value, err := g.Dist("Palermo", "Catania", "")
+		assert.Nil(t, err)
+		assert.InDelta(t, 166274.1516, value, 0.001)
+	})
+
+	t.Run("kilometers", func(t *testing.T) {
+		value, err := g.Dist("Palermo", "Catania", geo.Kilometers)
+		assert.Nil(t, err)
+		assert.InDelta(t, 166.2742, value, 0.001)
+	})
+
+	t.Run("non-existing member", func(t *testing.T) {
+		_, err := g.Dist("Palermo", "Rome", geo.Kilometers)
+		assert.Equal(t, geo.ErrMemberNotFound, err)
+	})
+}
+
+func TestRedisGeo_Hash(t *testing.T) {
+	g := newGeo(t)
+	defer g.Base().Delete()
+
+	value, err := g.Hash("Palermo", "Rome", "Catania")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sqc8b49rny0", "", "sqdtr74hyu0"}, value)
+}
+
+func TestRedisGeo_Pos(t *testing.T) {
+	g := newGeo(t)
+	defer g.Base().Delete()
+
+	positions, err := g.Pos("Palermo", "Rome")
+	assert.Nil(t, err)
+	if assert.Len(t, positions, 1) {
+		assert.InDelta(t, palermo.Longitude, positions["Palermo"].Longitude, 0.0001)
+		assert.InDelta(t, palermo.Latitude, positions["Palermo"].Latitude, 0.0001)
+	}
+}
+
+func TestRedisGeo_Remove(t *testing.T) {
+	g := newGeo(t)
+	defer g.Base().Delete()
+
+	value, err := g.Remove("Palermo", "Rome")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	positions, err := g.Pos("Palermo", "Catania")
+	assert.Nil(t, err)
+	assert.Len(t, positions, 1)
+}
+
+func TestRedisGeo_Search(t *testing.T) {
+	g := newGeo(t)
+	defer g.Base().Delete()
+
+	center := geo.Position{Longitude: 15, Latitude: 37}
+
+	t.Run("radius", func(t *testing.T) {
+		results, err := g.Search(geo.SearchQuery{FromPosition: center, Radius: 100, Unit: geo.Kilometers},
+			geo.SearchOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, []geo.SearchResult{{Member: "Catania"}}, results)
+	})
+
+	t.Run("box", func(t *testing.T) {
+		results, err := g.Search(geo.SearchQuery{FromPosition: center, Width: 400, Height: 400, Unit: geo.Kilometers},
+			geo.SearchOptions{Order: geo.Descending})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Palermo", "Catania"}, resultMembers(results))
+	})
+
+	t.Run("from member", func(t *testing.T) {
+		results, err := g.Search(geo.SearchQuery{FromMember: "Palermo", Radius: 200, Unit: geo.Kilometers},
+			geo.SearchOptions{Order: geo.Ascending})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Palermo", "Catania"}, resultMembers(results))
+	})
+
+	t.Run("non-existing member", func(t *testing.T) {
+		_, err := g.Search(geo.SearchQuery{FromMember: "Rome", Radius: 200}, geo.SearchOptions{})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		results, err := g.Search(geo.SearchQuery{FromPosition: center, Radius: 200, Unit: geo.Kilometers},
+			geo.SearchOptions{Order: geo.Ascending, Count: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Catania"}, resultMembers(results))
+
+		results, err = g.Search(geo.SearchQuery{FromPosition: center, Radius: 200, Unit: geo.Kilometers},
+			geo.SearchOptions{Count: 1, Any: true})
+		assert.Nil(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("with all", func(t *testing.T) {
+		results, err := g.Search(geo.SearchQuery{FromPosition: center, Radius: 200, Unit: geo.Kilometers},
+			geo.SearchOptions{Order: geo.Ascending, WithCoord: true, WithDist: true, WithHash: true})
+		assert.Nil(t, err)
+		if assert.Len(t, results, 2) {
+			assert.Equal(t, "Catania", results[0].Member)
+			assert.InDelta(t, 56.4413, results[0].Distance, 0.001)
+			assert.EqualValues(t, 3479447370796909, results[0].Hash)
+			assert.InDelta(t, catania.Longitude, results[0].Position.Longitude, 0.0001)
+			assert.InDelta(t, catania.Latitude, results[0].Position.Latitude, 0.0001)
+			assert.Equal(t, "Palermo", results[1].Member)
+			assert.InDelta(t, 190.4424, results[1].Distance, 0.001)
+		}
+	})
+
+	t.Run("with some", func(t *testing.T) {
+		results, err := g.Search(geo.SearchQuery{FromPosition: center, Radius: 100, Unit: geo.Kilometers},
+			geo.SearchOptions{WithHash: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []geo.SearchResult{{Member: "Catania", Hash: 3479447370796909}}, results)
+	})
+}
+
+func TestRedisGeo_SearchStore(t *testing.T) {
+	g := newGeo(t)
+	defer g.Base().Delete()
+
+	query := geo.SearchQuery{FromPosition: geo.Position{Longitude: 15, Latitude: 37}, Radius: 100, Unit: geo.Kilometers}
+
+	t.Run("positions", func(t *testing.T) {
+		store, err := g.SearchStore(test.RandomKey(), query, geo.SearchOptions{})
+		assert.Nil(t, err)
+		defer store.Base().Delete()
+
+		positions, err := store.Pos("Palermo", "Catania")
+		assert.Nil(t, err)
+		assert.Len(t, positions, 1)
+	})
+
+	t.Run("distances", func(t *testing.T) {
+		store, err := g.SearchStore(test.RandomKey(), query, geo.SearchOptions{StoreDist: true})
+		assert.Nil(t, err)
+		defer store.Base().Delete()
+
+		score, err := redis.Float64(conn.Do("ZSCORE", store.Base().Name(), "Catania"))
+		assert.Nil(t, err)
+		assert.InDelta(t, 56.4413, score, 0.001)
+	})
+}
+
+func TestRedisGeo_WithContext(t *testing.T) {
+	g := geo.NewRedisGeo(conn, test.RandomKey())
+	defer g.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := g.WithContext(ctx).Add([]geo.Location{palermo})
+	assert.Equal(t, context.Canceled, err)
+
+	exists, err := g.Base().Exists()
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,56 @@
+package geo
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+)
+
+// Pipelined is a Geo whose commands are queued in a redistypes.Batch instead of being sent
+// immediately. Each method returns a future that is resolved when the Batch is executed. The
+// methods work like the Geo methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Add queues the Redis command GEOADD. See Geo.Add.
+	Add(locations []Location, options ...AddOption) redistypes.Uint64Future
+
+	// Dist queues the Redis command GEODIST. See Geo.Dist. If either member doesn't
+	// exist, the future returns redis.ErrNil.
+	Dist(member1, member2 string, unit Unit) redistypes.Float64Future
+
+	// Remove queues the Redis command ZREM. See Geo.Remove.
+	Remove(members ...string) redistypes.Uint64Future
+}
+
+type pipelinedGeo struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined geospatial index that queues the commands of g in b.
+func NewPipelined(b redistypes.Batch, g Geo) Pipelined {
+	return &pipelinedGeo{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, g.Base()),
+	}
+}
+
+func (r pipelinedGeo) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedGeo) Add(locations []Location, options ...AddOption) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("GEOADD", addArgs(r.base.Name(), locations, options)...)}
+}
+
+func (r *pipelinedGeo) Dist(member1, member2 string, unit Unit) redistypes.Float64Future {
+	args := []interface{}{r.base.Name(), member1, member2}
+	if unit != "" {
+		args = append(args, string(unit))
+	}
+	return redistypes.Float64Future{Future: r.batch.Queue("GEODIST", args...)}
+}
+
+func (r *pipelinedGeo) Remove(members ...string) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("ZREM", memberArgs(r.base.Name(), members)...)}
+}
@@ -0,0 +1,44 @@
+package geo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/geo"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	g := geo.NewRedisGeo(conn, test.RandomKey())
+	defer g.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	pg := geo.NewPipelined(p, g)
+	assert.Equal(t, g.Base().Name(), pg.Base().Name())
+
+	add := pg.Add([]geo.Location{palermo, catania})
+	dist := pg.Dist("Palermo", "Catania", geo.Kilometers)
+	remove := pg.Remove("Palermo")
+	missing := pg.Dist("Palermo", "Catania", "")
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	count, err := add.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, count)
+
+	value, err := dist.Result()
+	assert.Nil(t, err)
+	assert.InDelta(t, 166.2742, value, 0.001)
+
+	count, err = remove.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	_, err = missing.Result()
+	assert.Equal(t, redis.ErrNil, err)
+}
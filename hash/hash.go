@@ -0,0 +1,406 @@
+// Package hash contains a Redis implementation of a hash, which maps string fields to
+// values.
+package hash
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// FieldValue is a field of a hash with its value.
+type FieldValue struct {
+	Field string
+	Value interface{}
+}
+
+// Hash is a Redis implementation of a hash.
+type Hash interface {
+	// Base returns the base Type.
+	Base() redistypes.Type
+
+	// CloneTo copies the hash to a new key called name using the Redis command COPY,
+	// and returns a Hash for the copy. If name already exists, it is overwritten. If
+	// the hash does not exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (Hash, error)
+
+	// Delete implements the Redis command HDEL. It removes one or more fields from the
+	// hash and returns the number of fields removed, not including the ones that
+	// didn't exist.
+	//
+	// See https://redis.io/commands/hdel.
+	Delete(fields ...string) (uint64, error)
+
+	// Exists implements the Redis command HEXISTS. It returns whether field exists in
+	// the hash.
+	//
+	// See https://redis.io/commands/hexists.
+	Exists(field string) (bool, error)
+
+	// Get implements the Redis command HGET. It returns the value of field. If the
+	// field or the hash doesn't exist, it returns nil.
+	//
+	// See https://redis.io/commands/hget.
+	Get(field string) (interface{}, error)
+
+	// GetAll implements the Redis command HGETALL. It returns all of the fields in
+	// the hash with their values. If the hash doesn't exist, the map is empty.
+	//
+	// See https://redis.io/commands/hgetall.
+	GetAll() (map[string]interface{}, error)
+
+	// GetAllStrings is like GetAll, but it converts the values to strings.
+	//
+	// See https://redis.io/commands/hgetall.
+	GetAllStrings() (map[string]string, error)
+
+	// IncrBy implements the Redis command HINCRBY. It increments the integer stored
+	// in field by increment and returns the new value. If the field doesn't exist,
+	// it is set to 0 before the increment.
+	//
+	// See https://redis.io/commands/hincrby.
+	IncrBy(field string, increment int64) (int64, error)
+
+	// IncrByFloat implements the Redis command HINCRBYFLOAT. It increments the floating
+	// point number stored in field by increment and returns the new value. If the
+	// field doesn't exist, it is set to 0 before the increment.
+	//
+	// See https://redis.io/commands/hincrbyfloat.
+	IncrByFloat(field string, increment float64) (float64, error)
+
+	// Keys implements the Redis command HKEYS. It returns the names of all of the
+	// fields in the hash.
+	//
+	// See https://redis.io/commands/hkeys.
+	Keys() ([]string, error)
+
+	// Length implements the Redis command HLEN. It returns the number of fields in the
+	// hash, or 0 if the hash doesn't exist.
+	//
+	// See https://redis.io/commands/hlen.
+	Length() (uint64, error)
+
+	// MultiGet implements the Redis command HMGET. It returns the values of fields, in
+	// the same order. The value of a field that doesn't exist is nil.
+	//
+	// See https://redis.io/commands/hmget.
+	MultiGet(fields ...string) ([]interface{}, error)
+
+	// MultiSet implements the Redis command HSET with several fields. It sets each
+	// field in values to its value and returns the number of fields that were added,
+	// not including the ones that were updated.
+	//
+	// See https://redis.io/commands/hset.
+	MultiSet(values map[string]interface{}) (uint64, error)
+
+	// RandomField implements the Redis command HRANDFIELD. It returns the name of a
+	// random field in the hash. If the hash doesn't exist, redistypes.ErrKeyNotFound
+	// is returned.
+	//
+	// See https://redis.io/commands/hrandfield.
+	RandomField() (string, error)
+
+	// RandomFields implements the Redis command HRANDFIELD with a count. If count is
+	// positive, it returns up to count distinct fields. If count is negative, it
+	// returns -count fields, which may include the same field more than once.
+	//
+	// See https://redis.io/commands/hrandfield.
+	RandomFields(count int64) ([]string, error)
+
+	// RandomFieldsWithValues implements the Redis command HRANDFIELD with a count and
+	// the WITHVALUES option. It works like RandomFields, but it returns the fields with
+	// their values, in the order Redis returned them. For a negative count, the same
+	// field may appear more than once.
+	//
+	// See https://redis.io/commands/hrandfield.
+	RandomFieldsWithValues(count int64) ([]FieldValue, error)
+
+	// Scan implements the Redis command HSCAN. It returns a Scanner that iterates over
+	// the fields of the hash with their values. If match is not empty, only the fields
+	// matching the glob-style pattern are returned. count is a hint for the number of
+	// fields fetched with each command, or 0 for the default.
+	//
+	// As described in the Redis documentation, a field may be returned more than once,
+	// and fields that are added or removed during the iteration may or may not be
+	// returned.
+	//
+	// See https://redis.io/commands/hscan.
+	Scan(match string, count int64) *Scanner
+
+	// Set implements the Redis command HSET. It sets field to value and returns true
+	// if the field was added, or false if it already existed and was updated.
+	//
+	// See https://redis.io/commands/hset.
+	Set(field string, value interface{}) (bool, error)
+
+	// SetNX implements the Redis command HSETNX. It sets field to value only if the
+	// field doesn't exist, and returns whether it was set.
+	//
+	// See https://redis.io/commands/hsetnx.
+	SetNX(field string, value interface{}) (bool, error)
+
+	// StrLen implements the Redis command HSTRLEN. It returns the length of the value
+	// of field, or 0 if the field doesn't exist.
+	//
+	// See https://redis.io/commands/hstrlen.
+	StrLen(field string) (uint64, error)
+
+	// Values implements the Redis command HVALS. It returns the values of all of the
+	// fields in the hash.
+	//
+	// See https://redis.io/commands/hvals.
+	Values() ([]interface{}, error)
+
+	// WithContext returns a copy of the Hash that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) Hash
+}
+
+type redisHash struct {
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
+}
+
+// NewRedisHash creates a Redis implementation of Hash given redigo connection conn and name.
+// The Redis key used to identify the Hash will be name.
+func NewRedisHash(conn redis.Conn, name string) Hash {
+	return NewRedisHashFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisHashFromProvider creates a Redis implementation of Hash given ConnProvider p and
+// name. Each command borrows a connection from p. The Redis key used to identify the Hash
+// will be name.
+func NewRedisHashFromProvider(p redistypes.ConnProvider, name string) Hash {
+	return &redisHash{
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
+	}
+}
+
+func (r redisHash) Base() redistypes.Type {
+	return r.base
+}
+
+func (r *redisHash) CloneTo(name string) (Hash, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisHashFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisHash) Delete(fields ...string) (uint64, error) {
+	args := fieldArgs(r.Base().Name(), fields)
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "HDEL", args...))
+}
+
+func (r *redisHash) Exists(field string) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "HEXISTS", r.Base().Name(), field))
+}
+
+func (r *redisHash) Get(field string) (interface{}, error) {
+	return internal.Do(r.ctx, r.provider, "HGET", r.Base().Name(), field)
+}
+
+func (r *redisHash) GetAll() (map[string]interface{}, error) {
+	return valueMap(internal.Do(r.ctx, r.provider, "HGETALL", r.Base().Name()))
+}
+
+func (r *redisHash) GetAllStrings() (map[string]string, error) {
+	return redis.StringMap(internal.Do(r.ctx, r.provider, "HGETALL", r.Base().Name()))
+}
+
+func (r *redisHash) IncrBy(field string, increment int64) (int64, error) {
+	return redis.Int64(internal.Do(r.ctx, r.provider, "HINCRBY", r.Base().Name(), field, increment))
+}
+
+func (r *redisHash) IncrByFloat(field string, increment float64) (float64, error) {
+	return redis.Float64(internal.Do(r.ctx, r.provider, "HINCRBYFLOAT", r.Base().Name(), field, increment))
+}
+
+func (r *redisHash) Keys() ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "HKEYS", r.Base().Name()))
+}
+
+func (r *redisHash) Length() (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "HLEN", r.Base().Name()))
+}
+
+func (r *redisHash) MultiGet(fields ...string) ([]interface{}, error) {
+	args := fieldArgs(r.Base().Name(), fields)
+	return redis.Values(internal.Do(r.ctx, r.provider, "HMGET", args...))
+}
+
+func (r *redisHash) MultiSet(values map[string]interface{}) (uint64, error) {
+	args := valueArgs(r.Base().Name(), values)
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "HSET", args...))
+}
+
+func (r *redisHash) RandomField() (string, error) {
+	field, err := redis.String(internal.Do(r.ctx, r.provider, "HRANDFIELD", r.Base().Name()))
+	if err == redis.ErrNil {
+		return "", redistypes.ErrKeyNotFound
+	}
+	return field, err
+}
+
+func (r *redisHash) RandomFields(count int64) ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "HRANDFIELD", r.Base().Name(), count))
+}
+
+func (r *redisHash) RandomFieldsWithValues(count int64) ([]FieldValue, error) {
+	return fieldValueSlice(internal.Do(r.ctx, r.provider, "HRANDFIELD", r.Base().Name(), count, "WITHVALUES"))
+}
+
+func (r *redisHash) Scan(match string, count int64) *Scanner {
+	return &Scanner{
+		scanner: internal.NewScanner(r.ctx, r.provider, "HSCAN", r.Base().Name(), match, count, 2),
+	}
+}
+
+func (r *redisHash) Set(field string, value interface{}) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "HSET", r.Base().Name(), field, value))
+}
+
+func (r *redisHash) SetNX(field string, value interface{}) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "HSETNX", r.Base().Name(), field, value))
+}
+
+func (r *redisHash) StrLen(field string) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "HSTRLEN", r.Base().Name(), field))
+}
+
+func (r *redisHash) Values() ([]interface{}, error) {
+	return redis.Values(internal.Do(r.ctx, r.provider, "HVALS", r.Base().Name()))
+}
+
+func (r *redisHash) WithContext(ctx context.Context) Hash {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisHash{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// Scanner iterates over the fields of a hash and their values. It is used like this:
+//
+//	s := h.Scan("", 0)
+//	for s.Next() {
+//		fmt.Println(s.Field(), s.Value())
+//	}
+//	if err := s.Err(); err != nil {
+//		// handle the error
+//	}
+//
+// The Scanner uses the context of the Hash it was created from, and stops when the
+// context is done.
+type Scanner struct {
+	scanner *internal.Scanner
+	field   string
+	err     error
+}
+
+// Next advances to the next field, which is then returned by Field and Value. It
+// returns false when there are no fields left or an error occurs.
+func (s *Scanner) Next() bool {
+	if s.err != nil || !s.scanner.Next() {
+		return false
+	}
+
+	s.field, s.err = redis.String(s.scanner.Item()[0], nil)
+	return s.err == nil
+}
+
+// Field returns the name of the current field.
+func (s *Scanner) Field() string {
+	return s.field
+}
+
+// Value returns the value of the current field.
+func (s *Scanner) Value() interface{} {
+	return s.scanner.Item()[1]
+}
+
+// Err returns the error that stopped the Scanner, if any.
+func (s *Scanner) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.scanner.Err()
+}
+
+// fieldArgs returns the arguments to a command that takes name followed by fields.
+func fieldArgs(name string, fields []string) []interface{} {
+	args := make([]interface{}, 0, 1+len(fields))
+	args = append(args, name)
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	return args
+}
+
+// valueArgs returns the arguments to HSET, which takes name followed by pairs of
+// fields and values.
+func valueArgs(name string, values map[string]interface{}) []interface{} {
+	args := make([]interface{}, 0, 1+2*len(values))
+	args = append(args, name)
+	for field, value := range values {
+		args = append(args, field, value)
+	}
+	return args
+}
+
+// valueMap converts a reply made up of pairs of fields and values to a map.
+func valueMap(reply interface{}, err error) (map[string]interface{}, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	} else if len(values)%2 != 0 {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	m := make(map[string]interface{}, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		field, err := redis.String(values[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		m[field] = values[i+1]
+	}
+	return m, nil
+}
+
+// fieldValueSlice converts a reply made up of pairs of fields and values to a slice of
+// FieldValues in the same order.
+func fieldValueSlice(reply interface{}, err error) ([]FieldValue, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	} else if len(values)%2 != 0 {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	fields := make([]FieldValue, len(values)/2)
+	for i := range fields {
+		if fields[i].Field, err = redis.String(values[2*i], nil); err != nil {
+			return nil, err
+		}
+		fields[i].Value = values[2*i+1]
+	}
+	return fields, nil
+}
@@ -0,0 +1,463 @@
+package hash_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/hash"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func ExampleNewRedisHash() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	h := hash.NewRedisHash(conn, "my_hash")
+	defer h.Base().Delete()
+
+	_, _ = h.MultiSet(map[string]interface{}{
+		"name":  "redistypes",
+		"stars": 10,
+	})
+	_, _ = h.IncrBy("stars", 5)
+
+	values, _ := h.GetAllStrings()
+	fmt.Println(values["name"], values["stars"])
+
+	// Output:
+	// redistypes 15
+}
+
+func TestNewRedisHashFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	h := hash.NewRedisHashFromProvider(pool, test.RandomKey())
+	defer h.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := h.Set(fmt.Sprint(i), i)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	value, err := h.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
+func TestRedisHash_CloneTo(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := h.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = h.Set("a", 1)
+
+		clone, err := h.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = h.Set("b", 2)
+
+		value, err := clone.Length()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+}
+
+func TestRedisHash_Delete(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := h.Delete("a")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = h.MultiSet(map[string]interface{}{"a": 1, "b": 2, "c": 3})
+
+		value, err := h.Delete("a", "b", "d")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+
+		keys, err := h.Keys()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"c"}, keys)
+	})
+}
+
+func TestRedisHash_Exists(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.Exists("a")
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	_, _ = h.Set("a", 1)
+
+	value, err = h.Exists("a")
+	assert.Nil(t, err)
+	assert.True(t, value)
+}
+
+func TestRedisHash_Get(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	t.Run("non-existing field", func(t *testing.T) {
+		value, err := h.Get("a")
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("existing field", func(t *testing.T) {
+		_, _ = h.Set("a", "abc")
+
+		value, err := redis.String(h.Get("a"))
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", value)
+	})
+}
+
+func TestRedisHash_GetAll(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		values, err := h.GetAll()
+		assert.Nil(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = h.MultiSet(map[string]interface{}{"a": 1, "b": "x"})
+
+		values, err := h.GetAll()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"a": []byte("1"), "b": []byte("x")}, values)
+
+		strs, err := h.GetAllStrings()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"a": "1", "b": "x"}, strs)
+	})
+}
+
+func TestRedisHash_IncrBy(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.IncrBy("a", 5)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, value)
+
+	value, err = h.IncrBy("a", -7)
+	assert.Nil(t, err)
+	assert.EqualValues(t, -2, value)
+
+	_, _ = h.Set("b", "abc")
+
+	_, err = h.IncrBy("b", 1)
+	assert.NotNil(t, err)
+}
+
+func TestRedisHash_IncrByFloat(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.IncrByFloat("a", 1.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, value)
+
+	value, err = h.IncrByFloat("a", 0.25)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.75, value)
+}
+
+func TestRedisHash_Keys(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	keys, err := h.Keys()
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+
+	_, _ = h.MultiSet(map[string]interface{}{"a": 1, "b": 2})
+
+	keys, err = h.Keys()
+	assert.Nil(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestRedisHash_Length(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	_, _ = h.MultiSet(map[string]interface{}{"a": 1, "b": 2})
+
+	value, err = h.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+}
+
+func TestRedisHash_MultiGet(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	_, _ = h.MultiSet(map[string]interface{}{"a": 1, "b": 2})
+
+	values, err := h.MultiGet("b", "c", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{[]byte("2"), nil, []byte("1")}, values)
+}
+
+func TestRedisHash_MultiSet(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.MultiSet(map[string]interface{}{"a": 1, "b": 2})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+
+	value, err = h.MultiSet(map[string]interface{}{"b": 3, "c": 4})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	strs, err := h.GetAllStrings()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "3", "c": "4"}, strs)
+}
+
+func TestRedisHash_RandomField(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := h.RandomField()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+
+		fields, err := h.RandomFields(2)
+		assert.Nil(t, err)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = h.MultiSet(map[string]interface{}{"a": 1, "b": 2})
+
+		field, err := h.RandomField()
+		assert.Nil(t, err)
+		assert.Contains(t, []string{"a", "b"}, field)
+
+		fields, err := h.RandomFields(5)
+		assert.Nil(t, err)
+		sort.Strings(fields)
+		assert.Equal(t, []string{"a", "b"}, fields)
+
+		fields, err = h.RandomFields(-5)
+		assert.Nil(t, err)
+		assert.Len(t, fields, 5)
+
+		values, err := h.RandomFieldsWithValues(5)
+		assert.Nil(t, err)
+		assert.Len(t, values, 2)
+		assert.Contains(t, values, hash.FieldValue{Field: "a", Value: []byte("1")})
+		assert.Contains(t, values, hash.FieldValue{Field: "b", Value: []byte("2")})
+
+		values, err = h.RandomFieldsWithValues(-5)
+		assert.Nil(t, err)
+		assert.Len(t, values, 5)
+		for _, value := range values {
+			assert.Contains(t, []hash.FieldValue{{Field: "a", Value: []byte("1")}, {Field: "b", Value: []byte("2")}}, value)
+		}
+	})
+}
+
+// scanReply is a redis.Conn that replies to every command with reply.
+type scanReply struct {
+	reply interface{}
+}
+
+func (c scanReply) Close() error                                       { return nil }
+func (c scanReply) Err() error                                         { return nil }
+func (c scanReply) Send(commandName string, args ...interface{}) error { return nil }
+func (c scanReply) Flush() error                                       { return nil }
+func (c scanReply) Receive() (interface{}, error)                      { return nil, nil }
+
+func (c scanReply) Do(commandName string, args ...interface{}) (interface{}, error) {
+	return c.reply, nil
+}
+
+func TestRedisHash_Scan(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	values := make(map[string]interface{})
+	for i := 0; i < 100; i++ {
+		values[fmt.Sprintf("field%d", i)] = i
+	}
+	_, _ = h.MultiSet(values)
+
+	t.Run("all fields", func(t *testing.T) {
+		fields := make(map[string]string)
+		s := h.Scan("", 10)
+		for s.Next() {
+			value, err := redis.String(s.Value(), nil)
+			assert.Nil(t, err)
+			fields[s.Field()] = value
+		}
+		assert.Nil(t, s.Err())
+		assert.Len(t, fields, 100)
+		assert.Equal(t, "42", fields["field42"])
+	})
+
+	t.Run("match", func(t *testing.T) {
+		fields := make(map[string]bool)
+		s := h.Scan("field1?", 0)
+		for s.Next() {
+			fields[s.Field()] = true
+		}
+		assert.Nil(t, s.Err())
+		assert.Len(t, fields, 10)
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		c := scanReply{reply: []interface{}{[]byte("0"), []interface{}{int64(1), []byte("a")}}}
+		s := hash.NewRedisHash(c, test.RandomKey()).Scan("", 0)
+		assert.False(t, s.Next())
+		assert.NotNil(t, s.Err())
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		s := h.WithContext(ctx).Scan("", 0)
+		assert.False(t, s.Next())
+		assert.Equal(t, context.Canceled, s.Err())
+	})
+}
+
+func TestRedisHash_Set(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.Set("a", 1)
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = h.Set("a", 2)
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	str, err := redis.String(h.Get("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", str)
+}
+
+func TestRedisHash_SetNX(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.SetNX("a", 1)
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = h.SetNX("a", 2)
+	assert.Nil(t, err)
+	assert.False(t, value)
+
+	str, err := redis.String(h.Get("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1", str)
+}
+
+func TestRedisHash_StrLen(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	value, err := h.StrLen("a")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	_, _ = h.Set("a", "abcde")
+
+	value, err = h.StrLen("a")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, value)
+}
+
+func TestRedisHash_Values(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	_, _ = h.Set("a", "x")
+
+	values, err := redis.Strings(h.Values())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x"}, values)
+}
+
+func TestRedisHash_WithContext(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := h.WithContext(ctx).Set("a", 1)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = h.WithContext(ctx).Base().Exists()
+	assert.Equal(t, context.Canceled, err)
+
+	value, err := h.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,93 @@
+package hash
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+)
+
+// Pipelined is a Hash whose commands are queued in a redistypes.Batch instead of being
+// sent immediately. Each method returns a future that is resolved when the Batch is
+// executed. The methods work like the Hash methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Delete queues the Redis command HDEL. See Hash.Delete.
+	Delete(fields ...string) redistypes.Uint64Future
+
+	// Exists queues the Redis command HEXISTS. See Hash.Exists.
+	Exists(field string) redistypes.BoolFuture
+
+	// Get queues the Redis command HGET. See Hash.Get.
+	Get(field string) *redistypes.Future
+
+	// IncrBy queues the Redis command HINCRBY. See Hash.IncrBy.
+	IncrBy(field string, increment int64) redistypes.Int64Future
+
+	// Length queues the Redis command HLEN. See Hash.Length.
+	Length() redistypes.Uint64Future
+
+	// MultiGet queues the Redis command HMGET. See Hash.MultiGet.
+	MultiGet(fields ...string) redistypes.ValuesFuture
+
+	// MultiSet queues the Redis command HSET with several fields. See Hash.MultiSet.
+	MultiSet(values map[string]interface{}) redistypes.Uint64Future
+
+	// Set queues the Redis command HSET. See Hash.Set.
+	Set(field string, value interface{}) redistypes.BoolFuture
+
+	// SetNX queues the Redis command HSETNX. See Hash.SetNX.
+	SetNX(field string, value interface{}) redistypes.BoolFuture
+}
+
+type pipelinedHash struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined hash that queues the commands of h in b.
+func NewPipelined(b redistypes.Batch, h Hash) Pipelined {
+	return &pipelinedHash{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, h.Base()),
+	}
+}
+
+func (r pipelinedHash) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedHash) Delete(fields ...string) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("HDEL", fieldArgs(r.base.Name(), fields)...)}
+}
+
+func (r *pipelinedHash) Exists(field string) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("HEXISTS", r.base.Name(), field)}
+}
+
+func (r *pipelinedHash) Get(field string) *redistypes.Future {
+	return r.batch.Queue("HGET", r.base.Name(), field)
+}
+
+func (r *pipelinedHash) IncrBy(field string, increment int64) redistypes.Int64Future {
+	return redistypes.Int64Future{Future: r.batch.Queue("HINCRBY", r.base.Name(), field, increment)}
+}
+
+func (r *pipelinedHash) Length() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("HLEN", r.base.Name())}
+}
+
+func (r *pipelinedHash) MultiGet(fields ...string) redistypes.ValuesFuture {
+	return redistypes.ValuesFuture{Future: r.batch.Queue("HMGET", fieldArgs(r.base.Name(), fields)...)}
+}
+
+func (r *pipelinedHash) MultiSet(values map[string]interface{}) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("HSET", valueArgs(r.base.Name(), values)...)}
+}
+
+func (r *pipelinedHash) Set(field string, value interface{}) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("HSET", r.base.Name(), field, value)}
+}
+
+func (r *pipelinedHash) SetNX(field string, value interface{}) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("HSETNX", r.base.Name(), field, value)}
+}
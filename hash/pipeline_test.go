@@ -0,0 +1,70 @@
+package hash_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/hash"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	h := hash.NewRedisHash(conn, test.RandomKey())
+	defer h.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	ph := hash.NewPipelined(p, h)
+	assert.Equal(t, h.Base().Name(), ph.Base().Name())
+
+	set := ph.Set("a", "1")
+	multiSet := ph.MultiSet(map[string]interface{}{"a": "2", "b": "3"})
+	setNX := ph.SetNX("a", "4")
+	incr := ph.IncrBy("b", 2)
+	get := ph.Get("a")
+	multiGet := ph.MultiGet("a", "b", "c")
+	del := ph.Delete("a", "c")
+	exists := ph.Exists("a")
+	length := ph.Length()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	ok, err := set.Result()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	count, err := multiSet.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	ok, err = setNX.Result()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	value, err := incr.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, value)
+
+	str, err := redis.String(get.Reply())
+	assert.Nil(t, err)
+	assert.Equal(t, "2", str)
+
+	values, err := multiGet.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{[]byte("2"), []byte("5"), nil}, values)
+
+	count, err = del.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	ok, err = exists.Result()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	count, err = length.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+}
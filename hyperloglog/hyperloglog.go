@@ -4,6 +4,9 @@
 package hyperloglog
 
 import (
+	"context"
+	"errors"
+
 	"github.com/MasterOfBinary/redistypes"
 	"github.com/MasterOfBinary/redistypes/internal"
 	"github.com/garyburd/redigo/redis"
@@ -21,6 +24,14 @@ type HyperLogLog interface {
 	// See https://redis.io/commands/pfadd.
 	Add(args ...interface{}) (bool, error)
 
+	// CloneTo copies the HyperLogLog to a new key called name using the Redis command
+	// COPY, and returns a HyperLogLog for the copy. If name already exists, it is
+	// overwritten. If the HyperLogLog does not exist, redistypes.ErrKeyNotFound is
+	// returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (HyperLogLog, error)
+
 	// Count implements the Redis command PFCOUNT. It returns the count of unique items added to the HyperLogLog,
 	// or an error if something went wrong.
 	//
@@ -32,19 +43,33 @@ type HyperLogLog interface {
 	//
 	// See https://redis.io/commands/pfmerge.
 	Merge(name string, other HyperLogLog) (HyperLogLog, error)
+
+	// WithContext returns a copy of the HyperLogLog that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) HyperLogLog
 }
 
 type redisHyperLogLog struct {
-	conn redis.Conn
-	base redistypes.Type
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
 }
 
 // NewRedisHyperLogLog creates a Redis implementation of HyperLogLog given redigo connection conn and name. The
 // Redis key used to identify the HyperLogLog will be name.
 func NewRedisHyperLogLog(conn redis.Conn, name string) HyperLogLog {
+	return NewRedisHyperLogLogFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisHyperLogLogFromProvider creates a Redis implementation of HyperLogLog given ConnProvider p and name.
+// Each command borrows a connection from p. The Redis key used to identify the HyperLogLog
+// will be name.
+func NewRedisHyperLogLogFromProvider(p redistypes.ConnProvider, name string) HyperLogLog {
 	return &redisHyperLogLog{
-		conn: conn,
-		base: redistypes.NewRedisType(conn, name),
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
 	}
 }
 
@@ -54,19 +79,76 @@ func (r redisHyperLogLog) Base() redistypes.Type {
 
 func (r redisHyperLogLog) Add(args ...interface{}) (bool, error) {
 	args = internal.PrependInterface(r.base.Name(), args...)
-	return redis.Bool(r.conn.Do("PFADD", args...))
+	return redis.Bool(internal.Do(r.ctx, r.provider, "PFADD", args...))
+}
+
+func (r *redisHyperLogLog) CloneTo(name string) (HyperLogLog, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisHyperLogLogFromProvider(r.provider, name).WithContext(r.ctx), nil
 }
 
 func (r *redisHyperLogLog) Count() (uint64, error) {
-	return redis.Uint64(r.conn.Do("PFCOUNT", r.base.Name()))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "PFCOUNT", r.base.Name()))
 }
 
 func (r *redisHyperLogLog) Merge(name string, other HyperLogLog) (HyperLogLog, error) {
-	_, err := redis.String(r.conn.Do("PFMERGE", name, r.base.Name(), other.Base().Name()))
+	_, err := redis.String(internal.Do(r.ctx, r.provider, "PFMERGE", name, r.base.Name(), other.Base().Name()))
 
 	if err != nil {
 		return nil, err
 	}
 
-	return NewRedisHyperLogLog(r.conn, name), nil
+	return NewRedisHyperLogLogFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisHyperLogLog) WithContext(ctx context.Context) HyperLogLog {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisHyperLogLog{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// Count implements the Redis command PFCOUNT with several keys. It borrows a connection
+// from p and returns the approximate number of unique items added to any of hlls,
+// without storing their union.
+//
+// See https://redis.io/commands/pfcount.
+func Count(ctx context.Context, p redistypes.ConnProvider, hlls ...HyperLogLog) (uint64, error) {
+	if len(hlls) == 0 {
+		return 0, errors.New("No HyperLogLogs given")
+	}
+
+	args := make([]interface{}, len(hlls))
+	for i, hll := range hlls {
+		args[i] = hll.Base().Name()
+	}
+
+	return redis.Uint64(internal.Do(ctx, p, "PFCOUNT", args...))
+}
+
+// MergeInto implements the Redis command PFMERGE. It borrows a connection from p and
+// merges sources into dest, keeping the items already in dest. dest is created if it
+// doesn't exist, and sources may include dest itself.
+//
+// See https://redis.io/commands/pfmerge.
+func MergeInto(ctx context.Context, p redistypes.ConnProvider, dest HyperLogLog, sources ...HyperLogLog) error {
+	args := make([]interface{}, 0, 1+len(sources))
+	args = append(args, dest.Base().Name())
+	for _, source := range sources {
+		args = append(args, source.Base().Name())
+	}
+
+	_, err := internal.Do(ctx, p, "PFMERGE", args...)
+	return err
 }
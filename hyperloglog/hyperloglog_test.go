@@ -1,12 +1,15 @@
 package hyperloglog_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/MasterOfBinary/redistypes"
 	"github.com/MasterOfBinary/redistypes/hyperloglog"
 	"github.com/MasterOfBinary/redistypes/internal"
 	"github.com/MasterOfBinary/redistypes/internal/test"
@@ -37,6 +40,34 @@ func ExampleNewRedisHyperLogLog() {
 	// Output: Count: 0
 }
 
+func TestNewRedisHyperLogLogFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	hll := hyperloglog.NewRedisHyperLogLogFromProvider(pool, test.RandomKey())
+	defer hll.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := hll.Add(i)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	count, err := hll.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, count)
+}
+
 func TestRedisHyperLogLog_Add(t *testing.T) {
 	hll := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
 	defer test.DeleteKey(hll.Base().Name(), conn)
@@ -77,6 +108,30 @@ func TestRedisHyperLogLog_Add(t *testing.T) {
 	}
 }
 
+func TestRedisHyperLogLog_CloneTo(t *testing.T) {
+	hll := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+	defer hll.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := hll.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = hll.Add("abc", "def")
+
+		clone, err := hll.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = hll.Add("ghi")
+
+		count, err := clone.Count()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, count)
+	})
+}
+
 func TestRedisHyperLogLog_Count(t *testing.T) {
 	hll := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
 	defer test.DeleteKey(hll.Base().Name(), conn)
@@ -181,6 +236,104 @@ func TestRedisHyperLogLog_Merge(t *testing.T) {
 	}
 }
 
+func TestRedisHyperLogLog_WithContext(t *testing.T) {
+	hll := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+	defer hll.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := hll.WithContext(ctx).Add("abc")
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = hll.WithContext(ctx).Base().Exists()
+	assert.Equal(t, context.Canceled, err)
+
+	count, err := hll.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestCount(t *testing.T) {
+	hll1 := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+	defer hll1.Base().Delete()
+	hll2 := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+	defer hll2.Base().Delete()
+
+	_, _ = hll1.Add("abc", "def", "ghi")
+	_, _ = hll2.Add("ghi", "jkl")
+
+	t.Run("no HyperLogLogs", func(t *testing.T) {
+		_, err := hyperloglog.Count(context.Background(), redistypes.SingleConn(conn))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("one HyperLogLog", func(t *testing.T) {
+		count, err := hyperloglog.Count(context.Background(), redistypes.SingleConn(conn), hll1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, count)
+	})
+
+	t.Run("several HyperLogLogs", func(t *testing.T) {
+		empty := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+		count, err := hyperloglog.Count(context.Background(), redistypes.SingleConn(conn), hll1, hll2, empty)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 4, count)
+
+		exists, _ := hll1.Base().Exists()
+		assert.True(t, exists)
+		count, _ = hll1.Count()
+		assert.EqualValues(t, 3, count)
+	})
+}
+
+func TestMergeInto(t *testing.T) {
+	sources := make([]hyperloglog.HyperLogLog, 10)
+	for i := range sources {
+		sources[i] = hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+		defer sources[i].Base().Delete()
+		_, _ = sources[i].Add(fmt.Sprintf("day%d", i), "common")
+	}
+
+	t.Run("new destination", func(t *testing.T) {
+		dest := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+		defer dest.Base().Delete()
+
+		err := hyperloglog.MergeInto(context.Background(), redistypes.SingleConn(conn), dest, sources...)
+		assert.Nil(t, err)
+
+		count, err := dest.Count()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 11, count)
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		dest := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+		defer dest.Base().Delete()
+		_, _ = dest.Add("existing")
+
+		err := hyperloglog.MergeInto(context.Background(), redistypes.SingleConn(conn), dest, sources[:2]...)
+		assert.Nil(t, err)
+
+		count, err := dest.Count()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 4, count)
+	})
+
+	t.Run("destination in sources", func(t *testing.T) {
+		dest, err := sources[0].CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer dest.Base().Delete()
+
+		err = hyperloglog.MergeInto(context.Background(), redistypes.SingleConn(conn), dest, dest, sources[1])
+		assert.Nil(t, err)
+
+		count, err := dest.Count()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, count)
+	})
+}
+
 func TestMain(m *testing.M) {
 	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
 	if err != nil {
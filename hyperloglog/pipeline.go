@@ -0,0 +1,46 @@
+package hyperloglog
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+)
+
+// Pipelined is a HyperLogLog whose commands are queued in a redistypes.Batch instead of
+// being sent immediately. Each method returns a future that is resolved when the Batch
+// is executed. The methods work like the HyperLogLog methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Add queues the Redis command PFADD. See HyperLogLog.Add.
+	Add(args ...interface{}) redistypes.BoolFuture
+
+	// Count queues the Redis command PFCOUNT. See HyperLogLog.Count.
+	Count() redistypes.Uint64Future
+}
+
+type pipelinedHyperLogLog struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined HyperLogLog that queues the commands of hll in b.
+func NewPipelined(b redistypes.Batch, hll HyperLogLog) Pipelined {
+	return &pipelinedHyperLogLog{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, hll.Base()),
+	}
+}
+
+func (r pipelinedHyperLogLog) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedHyperLogLog) Add(args ...interface{}) redistypes.BoolFuture {
+	args = internal.PrependInterface(r.base.Name(), args...)
+	return redistypes.BoolFuture{Future: r.batch.Queue("PFADD", args...)}
+}
+
+func (r *pipelinedHyperLogLog) Count() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("PFCOUNT", r.base.Name())}
+}
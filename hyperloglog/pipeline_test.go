@@ -0,0 +1,39 @@
+package hyperloglog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/hyperloglog"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	hll := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+	defer hll.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	phll := hyperloglog.NewPipelined(p, hll)
+	assert.Equal(t, hll.Base().Name(), phll.Base().Name())
+
+	add := phll.Add("abc", "def")
+	addExisting := phll.Add("abc")
+	count := phll.Count()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	altered, err := add.Result()
+	assert.Nil(t, err)
+	assert.True(t, altered)
+
+	altered, err = addExisting.Result()
+	assert.Nil(t, err)
+	assert.False(t, altered)
+
+	value, err := count.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+}
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// ConnProvider provides connections to Redis. It has the same method set as
+// redistypes.ConnProvider.
+type ConnProvider interface {
+	Get() redis.Conn
+}
+
+// contextProvider is implemented by providers that can wait for a connection until
+// a context is done, such as *redis.Pool.
+type contextProvider interface {
+	GetContext(ctx context.Context) (redis.Conn, error)
+}
+
+// GetConn borrows a connection from p. If p supports it and ctx can be cancelled,
+// GetConn stops waiting for a connection when ctx is done. The connection must be
+// closed by the caller.
+func GetConn(ctx context.Context, p ConnProvider) (redis.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if cp, ok := p.(contextProvider); ok && ctx.Done() != nil {
+		return cp.GetContext(ctx)
+	}
+
+	conn := p.Get()
+	return conn, conn.Err()
+}
+
+// Do borrows a connection from p and sends cmd with args on it using DoContext.
+func Do(ctx context.Context, p ConnProvider, cmd string, args ...interface{}) (interface{}, error) {
+	conn, err := GetConn(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return DoContext(ctx, conn, cmd, args...)
+}
+
+// unblockRetryInterval is how often unblockableDo sends CLIENT UNBLOCK again until the
+// blocking command returns, and how long it waits for a connection to send it on.
+const unblockRetryInterval = 100 * time.Millisecond
+
+// BlockingDo borrows a connection from p and sends a blocking command on it. If ctx
+// can be cancelled and p hands out a separate connection for each command, like
+// *redis.Pool, the command is sent once and interrupted with the Redis command
+// CLIENT UNBLOCK as soon as ctx is done, so the connection can still be used.
+// Otherwise BlockingDoContext is used.
+func BlockingDo(ctx context.Context, p ConnProvider, timeout time.Duration, cmd string,
+	args func(seconds int64) []interface{}) (interface{}, error) {
+	conn, err := GetConn(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	cp, ok := p.(contextProvider)
+	if !ok || ctx.Done() == nil {
+		return BlockingDoContext(ctx, conn, timeout, cmd, args)
+	}
+	return unblockableDo(ctx, cp, conn, timeout, cmd, args)
+}
+
+// unblockableDo sends a blocking command on conn, and sends CLIENT UNBLOCK for conn on
+// another connection from p once ctx is done. ctx may be done before the command
+// reaches the server, so CLIENT UNBLOCK is sent again every unblockRetryInterval until
+// it unblocks the client or the command returns. unblockableDo doesn't return until
+// then, so it can't interrupt a later command on conn.
+func unblockableDo(ctx context.Context, p contextProvider, conn redis.Conn, timeout time.Duration, cmd string,
+	args func(seconds int64) []interface{}) (interface{}, error) {
+	id, err := redis.Int64(conn.Do("CLIENT", "ID"))
+	if err != nil {
+		return nil, err
+	} else if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		ticker := time.NewTicker(unblockRetryInterval)
+		defer ticker.Stop()
+		for !unblock(p, id) {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	reply, err := redis.DoWithTimeout(conn, 0, cmd, args(int64(timeout/time.Second))...)
+	close(done)
+	<-finished
+
+	if err == nil && reply == nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
+
+// unblock sends CLIENT UNBLOCK for the client id on a connection from p, and returns
+// true if the client was blocked. It waits at most unblockRetryInterval for the
+// connection, so an exhausted pool can't block it forever.
+func unblock(p contextProvider, id int64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), unblockRetryInterval)
+	defer cancel()
+
+	conn, err := p.GetContext(ctx)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	n, err := redis.Int64(conn.Do("CLIENT", "UNBLOCK", id))
+	return err == nil && n == 1
+}
+
+// NopCloseConn returns a connection that sends everything to conn, except Close,
+// which does nothing.
+func NopCloseConn(conn redis.Conn) redis.Conn {
+	return nopCloseConn{conn}
+}
+
+type nopCloseConn struct {
+	redis.Conn
+}
+
+func (c nopCloseConn) Close() error {
+	return nil
+}
+
+func (c nopCloseConn) DoWithTimeout(timeout time.Duration, cmd string, args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, cmd, args...)
+}
+
+func (c nopCloseConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// ErrorConn returns a connection whose methods all return err, except Close.
+func ErrorConn(err error) redis.Conn {
+	return errorConn{err}
+}
+
+type errorConn struct {
+	err error
+}
+
+func (c errorConn) Close() error                                   { return nil }
+func (c errorConn) Err() error                                     { return c.err }
+func (c errorConn) Flush() error                                   { return c.err }
+func (c errorConn) Send(string, ...interface{}) error              { return c.err }
+func (c errorConn) Receive() (interface{}, error)                  { return nil, c.err }
+func (c errorConn) Do(string, ...interface{}) (interface{}, error) { return nil, c.err }
+
+func (c errorConn) DoWithTimeout(time.Duration, string, ...interface{}) (interface{}, error) {
+	return nil, c.err
+}
+
+func (c errorConn) ReceiveWithTimeout(time.Duration) (interface{}, error) {
+	return nil, c.err
+}
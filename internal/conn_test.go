@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubProvider is a ConnProvider that always provides conn and counts how many
+// connections were borrowed and closed.
+type stubProvider struct {
+	conn     redis.Conn
+	borrowed int
+	closed   int
+}
+
+func (p *stubProvider) Get() redis.Conn {
+	p.borrowed++
+	return closeCounter{Conn: NopCloseConn(p.conn), closed: &p.closed}
+}
+
+type closeCounter struct {
+	redis.Conn
+	closed *int
+}
+
+func (c closeCounter) Close() error {
+	*c.closed++
+	return nil
+}
+
+func TestDo(t *testing.T) {
+	t.Run("borrows and closes connection", func(t *testing.T) {
+		p := &stubProvider{conn: &stubConn{replies: []interface{}{"OK"}}}
+		reply, err := Do(context.Background(), p, "SET", "a", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", reply)
+		assert.Equal(t, 1, p.borrowed)
+		assert.Equal(t, 1, p.closed)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := &stubProvider{conn: &stubConn{}}
+		_, err := Do(ctx, p, "GET", "a")
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, p.borrowed)
+	})
+
+	t.Run("broken connection", func(t *testing.T) {
+		wantErr := errors.New("broken")
+		p := &stubProvider{conn: ErrorConn(wantErr)}
+		_, err := Do(context.Background(), p, "GET", "a")
+		assert.Equal(t, wantErr, err)
+	})
+}
+
+func TestBlockingDo(t *testing.T) {
+	conn := &stubConn{replies: []interface{}{[]interface{}{"list", "abc"}}}
+	p := &stubProvider{conn: conn}
+
+	reply, err := BlockingDo(context.Background(), p, 0, "BLPOP", func(seconds int64) []interface{} {
+		return []interface{}{"list", seconds}
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"list", "abc"}, reply)
+	assert.Equal(t, [][]interface{}{{"BLPOP", "list", int64(0)}}, conn.commands)
+	assert.Equal(t, 1, p.closed)
+}
+
+// unblockProvider is a ConnProvider that hands out a separate connection for each
+// command, like *redis.Pool. Its connections block on BLPOP until CLIENT UNBLOCK is
+// sent for the client ID of the blocked connection. CLIENT UNBLOCK sent before BLPOP
+// blocks does nothing, as in Redis. onCommand is called before each command is run.
+type unblockProvider struct {
+	mu        sync.Mutex
+	blocked   chan struct{}
+	onCommand func(cmd string)
+	commands  chan []interface{}
+}
+
+func newUnblockProvider(onCommand func(cmd string)) *unblockProvider {
+	return &unblockProvider{
+		onCommand: onCommand,
+		commands:  make(chan []interface{}, 100),
+	}
+}
+
+func (p *unblockProvider) Get() redis.Conn {
+	return &unblockConn{provider: p}
+}
+
+func (p *unblockProvider) GetContext(ctx context.Context) (redis.Conn, error) {
+	return p.Get(), nil
+}
+
+type unblockConn struct {
+	stubConn
+	provider *unblockProvider
+}
+
+func (c *unblockConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	return c.DoWithTimeout(0, cmd, args...)
+}
+
+func (c *unblockConn) DoWithTimeout(timeout time.Duration, cmd string, args ...interface{}) (interface{}, error) {
+	p := c.provider
+	p.commands <- PrependInterface(cmd, args...)
+	if p.onCommand != nil {
+		p.onCommand(cmd)
+	}
+
+	switch {
+	case cmd == "CLIENT" && args[0] == "ID":
+		return int64(7), nil
+	case cmd == "CLIENT" && args[0] == "UNBLOCK" && args[1] == int64(7):
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.blocked == nil {
+			return int64(0), nil
+		}
+		close(p.blocked)
+		p.blocked = nil
+		return int64(1), nil
+	case cmd == "BLPOP":
+		blocked := make(chan struct{})
+		p.mu.Lock()
+		p.blocked = blocked
+		p.mu.Unlock()
+		<-blocked
+		return nil, nil
+	}
+	return nil, errors.New("unexpected command")
+}
+
+// blockingCommands runs BLPOP on p with BlockingDo and returns the commands that were
+// sent and the error.
+func blockingCommands(ctx context.Context, p *unblockProvider) ([][]interface{}, error) {
+	_, err := BlockingDo(ctx, p, 0, "BLPOP", func(seconds int64) []interface{} {
+		return []interface{}{"list", seconds}
+	})
+
+	close(p.commands)
+	var commands [][]interface{}
+	for command := range p.commands {
+		commands = append(commands, command)
+	}
+	return commands, err
+}
+
+func TestBlockingDo_unblock(t *testing.T) {
+	t.Run("blocked", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := newUnblockProvider(nil)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		commands, err := blockingCommands(ctx, p)
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, time.Since(start) < time.Second)
+		assert.Equal(t, [][]interface{}{
+			{"CLIENT", "ID"},
+			{"BLPOP", "list", int64(0)},
+			{"CLIENT", "UNBLOCK", int64(7)},
+		}, commands)
+	})
+
+	t.Run("cancelled before blocking", func(t *testing.T) {
+		// ctx is done before BLPOP blocks, so the first CLIENT UNBLOCK does nothing.
+		ctx, cancel := context.WithCancel(context.Background())
+		p := newUnblockProvider(func(cmd string) {
+			if cmd == "BLPOP" {
+				cancel()
+				time.Sleep(50 * time.Millisecond)
+			}
+		})
+
+		start := time.Now()
+		commands, err := blockingCommands(ctx, p)
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, time.Since(start) < time.Second)
+		assert.Equal(t, []interface{}{"BLPOP", "list", int64(0)}, commands[1])
+		assert.True(t, len(commands) > 3)
+		for _, command := range commands[2:] {
+			assert.Equal(t, []interface{}{"CLIENT", "UNBLOCK", int64(7)}, command)
+		}
+	})
+
+	t.Run("cancelled during CLIENT ID", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := newUnblockProvider(func(cmd string) {
+			if cmd == "CLIENT" {
+				cancel()
+			}
+		})
+
+		commands, err := blockingCommands(ctx, p)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, [][]interface{}{{"CLIENT", "ID"}}, commands)
+	})
+}
+
+func TestNopCloseConn(t *testing.T) {
+	conn := &stubConn{}
+	nopConn := NopCloseConn(conn)
+
+	assert.Nil(t, nopConn.Close())
+
+	_, err := redis.DoWithTimeout(nopConn, time.Second, "GET", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{time.Second}, conn.timeouts)
+}
+
+func TestErrorConn(t *testing.T) {
+	wantErr := errors.New("broken")
+	conn := ErrorConn(wantErr)
+
+	assert.Nil(t, conn.Close())
+	assert.Equal(t, wantErr, conn.Err())
+
+	_, err := conn.Do("GET", "a")
+	assert.Equal(t, wantErr, err)
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	// blockingPollInterval is how long each attempt of a cancellable blocking command
+	// blocks on the server.
+	blockingPollInterval = time.Second
+
+	// blockingReadMargin is added to blockingPollInterval to get the read timeout of
+	// each attempt of a cancellable blocking command.
+	blockingReadMargin = time.Second
+)
+
+// DoContext sends cmd with args on conn and returns the reply. If ctx is done before
+// the command is sent, ctx.Err() is returned. If ctx has a deadline, the time left
+// until it is used as the read timeout for the reply, and ctx.Err() is returned if
+// it is exceeded. As with redigo's DoWithTimeout, conn can't be used after that.
+func DoContext(ctx context.Context, conn redis.Conn, cmd string, args ...interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return conn.Do(cmd, args...)
+	}
+
+	timeout := deadline.Sub(time.Now())
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+
+	reply, err := redis.DoWithTimeout(conn, timeout, cmd, args...)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
+
+// BlockingDoContext sends a blocking command such as BLPOP on conn and returns the
+// reply. The command blocks for timeout, which must be a multiple of one second, or
+// indefinitely if timeout is 0. args returns the arguments of the command given the
+// number of seconds it should block.
+//
+// If ctx can't be cancelled, the command is sent once. Otherwise it is sent repeatedly,
+// blocking one second at a time, until a non-nil reply is received, timeout is reached
+// or ctx is done. Cancellation is therefore noticed within about a second, and conn
+// can still be used afterwards. If ctx has a deadline, the read timeout of each attempt
+// ends at the deadline, and ctx.Err() is returned if it is exceeded. As with DoContext,
+// conn can't be used after that. BlockingDo interrupts the command as soon as ctx is
+// done when it can.
+func BlockingDoContext(ctx context.Context, conn redis.Conn, timeout time.Duration, cmd string,
+	args func(seconds int64) []interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	seconds := int64(timeout / time.Second)
+	if ctx.Done() == nil {
+		return conn.Do(cmd, args(seconds)...)
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	pollSeconds := int64(blockingPollInterval / time.Second)
+	for i := int64(0); seconds == 0 || i < seconds; i += pollSeconds {
+		readTimeout := blockingPollInterval + blockingReadMargin
+		if hasDeadline {
+			left := deadline.Sub(time.Now())
+			if left <= 0 {
+				return nil, context.DeadlineExceeded
+			} else if left < readTimeout {
+				readTimeout = left
+			}
+		}
+
+		reply, err := redis.DoWithTimeout(conn, readTimeout, cmd, args(pollSeconds)...)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if err != nil || reply != nil {
+			return reply, err
+		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// ReceiveContext receives a single reply from conn. If ctx is done before the reply is
+// read, ctx.Err() is returned. If ctx has a deadline, it is used in the same way as by
+// DoContext.
+func ReceiveContext(ctx context.Context, conn redis.Conn) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return conn.Receive()
+	}
+
+	timeout := deadline.Sub(time.Now())
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+
+	reply, err := redis.ReceiveWithTimeout(conn, timeout)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubConn is a redis.Conn that records the commands sent to it. Each command gets
+// the next reply in replies, or nil if there are none left.
+type stubConn struct {
+	commands [][]interface{}
+	timeouts []time.Duration
+	replies  []interface{}
+	onDo     func()
+}
+
+func (c *stubConn) nextReply() interface{} {
+	if len(c.replies) == 0 {
+		return nil
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply
+}
+
+func (c *stubConn) Close() error { return nil }
+func (c *stubConn) Err() error   { return nil }
+func (c *stubConn) Flush() error { return nil }
+
+func (c *stubConn) Send(cmd string, args ...interface{}) error { return nil }
+
+func (c *stubConn) Receive() (interface{}, error) {
+	return c.ReceiveWithTimeout(0)
+}
+
+func (c *stubConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	c.timeouts = append(c.timeouts, timeout)
+	return c.nextReply(), nil
+}
+
+func (c *stubConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	return c.DoWithTimeout(0, cmd, args...)
+}
+
+func (c *stubConn) DoWithTimeout(timeout time.Duration, cmd string, args ...interface{}) (interface{}, error) {
+	c.commands = append(c.commands, PrependInterface(cmd, args...))
+	c.timeouts = append(c.timeouts, timeout)
+	if c.onDo != nil {
+		c.onDo()
+	}
+
+	return c.nextReply(), nil
+}
+
+func TestDoContext(t *testing.T) {
+	t.Run("background", func(t *testing.T) {
+		conn := &stubConn{replies: []interface{}{"OK"}}
+		reply, err := DoContext(context.Background(), conn, "SET", "a", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", reply)
+		assert.Equal(t, [][]interface{}{{"SET", "a", 1}}, conn.commands)
+		assert.Equal(t, []time.Duration{0}, conn.timeouts)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		conn := &stubConn{}
+		_, err := DoContext(ctx, conn, "GET", "a")
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, conn.commands)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		conn := &stubConn{}
+		_, err := DoContext(ctx, conn, "GET", "a")
+		assert.Nil(t, err)
+		assert.Len(t, conn.timeouts, 1)
+		assert.True(t, conn.timeouts[0] > 0 && conn.timeouts[0] <= time.Minute)
+	})
+}
+
+func TestBlockingDoContext(t *testing.T) {
+	args := func(seconds int64) []interface{} {
+		return []interface{}{"list", seconds}
+	}
+
+	t.Run("background", func(t *testing.T) {
+		conn := &stubConn{}
+		reply, err := BlockingDoContext(context.Background(), conn, 5*time.Second, "BLPOP", args)
+		assert.Nil(t, err)
+		assert.Nil(t, reply)
+		assert.Equal(t, [][]interface{}{{"BLPOP", "list", int64(5)}}, conn.commands)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		conn := &stubConn{}
+		reply, err := BlockingDoContext(ctx, conn, 3*time.Second, "BLPOP", args)
+		assert.Nil(t, err)
+		assert.Nil(t, reply)
+		assert.Len(t, conn.commands, 3)
+		for _, command := range conn.commands {
+			assert.Equal(t, []interface{}{"BLPOP", "list", int64(1)}, command)
+		}
+	})
+
+	t.Run("reply", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		conn := &stubConn{replies: []interface{}{nil, []interface{}{"list", "abc"}}}
+		reply, err := BlockingDoContext(ctx, conn, 0, "BLPOP", args)
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{"list", "abc"}, reply)
+		assert.Len(t, conn.commands, 2)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		conn := &stubConn{}
+		conn.onDo = func() {
+			if len(conn.commands) == 2 {
+				cancel()
+			}
+		}
+
+		_, err := BlockingDoContext(ctx, conn, 0, "BLPOP", args)
+		assert.Equal(t, context.Canceled, err)
+		assert.Len(t, conn.commands, 2)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		conn := &stubConn{}
+		conn.onDo = func() {
+			time.Sleep(60 * time.Millisecond)
+		}
+
+		_, err := BlockingDoContext(ctx, conn, 0, "BLPOP", args)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Len(t, conn.commands, 2)
+		for _, timeout := range conn.timeouts {
+			assert.True(t, timeout > 0 && timeout <= 100*time.Millisecond)
+		}
+	})
+}
+
+func TestReceiveContext(t *testing.T) {
+	t.Run("background", func(t *testing.T) {
+		conn := &stubConn{replies: []interface{}{"OK"}}
+		reply, err := ReceiveContext(context.Background(), conn)
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", reply)
+		assert.Equal(t, []time.Duration{0}, conn.timeouts)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		conn := &stubConn{replies: []interface{}{"OK"}}
+		_, err := ReceiveContext(ctx, conn)
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, conn.timeouts)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		conn := &stubConn{replies: []interface{}{"OK"}}
+		reply, err := ReceiveContext(ctx, conn)
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", reply)
+		assert.True(t, conn.timeouts[0] > 0 && conn.timeouts[0] <= time.Minute)
+	})
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// Scanner iterates over the reply of a command in the SCAN family, such as HSCAN or
+// SSCAN, fetching a page of elements each time the previous one is used up. Each item
+// is made up of size consecutive elements of the reply, for example a field and its
+// value for HSCAN.
+type Scanner struct {
+	ctx      context.Context
+	provider ConnProvider
+	cmd      string
+	key      string
+	options  []interface{}
+	size     int
+
+	cursor  string
+	started bool
+	page    []interface{}
+	item    []interface{}
+	err     error
+}
+
+// NewScanner creates a Scanner that sends cmd for key using ctx and p. If match is
+// not empty it is sent as the MATCH option, and if count is greater than 0 it is sent
+// as the COUNT option.
+func NewScanner(ctx context.Context, p ConnProvider, cmd, key, match string, count int64, size int) *Scanner {
+	var options []interface{}
+	if match != "" {
+		options = append(options, "MATCH", match)
+	}
+	if count > 0 {
+		options = append(options, "COUNT", count)
+	}
+
+	return &Scanner{
+		ctx:      ctx,
+		provider: p,
+		cmd:      cmd,
+		key:      key,
+		options:  options,
+		size:     size,
+		cursor:   "0",
+	}
+}
+
+// Next advances to the next item, which is then returned by Item. It returns false
+// when there are no items left, when a command fails or when ctx is done.
+func (s *Scanner) Next() bool {
+	s.item = nil
+	if s.err != nil {
+		return false
+	}
+	if s.err = s.ctx.Err(); s.err != nil {
+		return false
+	}
+
+	for len(s.page) == 0 {
+		if s.started && s.cursor == "0" {
+			return false
+		}
+		if s.err = s.fetch(); s.err != nil {
+			return false
+		}
+	}
+
+	s.item = s.page[:s.size]
+	s.page = s.page[s.size:]
+	return true
+}
+
+// Item returns the elements of the current item.
+func (s *Scanner) Item() []interface{} {
+	return s.item
+}
+
+// Err returns the error that stopped the Scanner, if any.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+func (s *Scanner) fetch() error {
+	args := append([]interface{}{s.key, s.cursor}, s.options...)
+	values, err := redis.Values(Do(s.ctx, s.provider, s.cmd, args...))
+	if err != nil {
+		return err
+	} else if len(values) != 2 {
+		return errors.New("Unexpected response length")
+	}
+
+	cursor, err := redis.String(values[0], nil)
+	if err != nil {
+		return err
+	}
+	page, err := redis.Values(values[1], nil)
+	if err != nil {
+		return err
+	} else if len(page)%s.size != 0 {
+		return errors.New("Unexpected response length")
+	}
+
+	s.cursor = cursor
+	s.started = true
+	s.page = page
+	return nil
+}
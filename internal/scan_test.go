@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanner(t *testing.T) {
+	t.Run("pages", func(t *testing.T) {
+		conn := &stubConn{replies: []interface{}{
+			[]interface{}{[]byte("7"), []interface{}{"a", "1", "b", "2"}},
+			[]interface{}{[]byte("3"), []interface{}{}},
+			[]interface{}{[]byte("0"), []interface{}{"c", "3"}},
+		}}
+		s := NewScanner(context.Background(), &stubProvider{conn: conn}, "HSCAN", "key", "*", 10, 2)
+
+		var items [][]interface{}
+		for s.Next() {
+			items = append(items, s.Item())
+		}
+		assert.Nil(t, s.Err())
+		assert.Equal(t, [][]interface{}{{"a", "1"}, {"b", "2"}, {"c", "3"}}, items)
+		assert.Equal(t, [][]interface{}{
+			{"HSCAN", "key", "0", "MATCH", "*", "COUNT", int64(10)},
+			{"HSCAN", "key", "7", "MATCH", "*", "COUNT", int64(10)},
+			{"HSCAN", "key", "3", "MATCH", "*", "COUNT", int64(10)},
+		}, conn.commands)
+	})
+
+	t.Run("unexpected response", func(t *testing.T) {
+		conn := &stubConn{replies: []interface{}{
+			[]interface{}{[]byte("0"), []interface{}{"a"}},
+		}}
+		s := NewScanner(context.Background(), &stubProvider{conn: conn}, "HSCAN", "key", "", 0, 2)
+
+		assert.False(t, s.Next())
+		assert.NotNil(t, s.Err())
+		assert.Equal(t, [][]interface{}{{"HSCAN", "key", "0"}}, conn.commands)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		conn := &stubConn{replies: []interface{}{
+			[]interface{}{[]byte("0"), []interface{}{"a", "b"}},
+		}}
+		s := NewScanner(ctx, &stubProvider{conn: conn}, "SSCAN", "key", "", 0, 1)
+
+		assert.True(t, s.Next())
+		cancel()
+		assert.False(t, s.Next())
+		assert.Equal(t, context.Canceled, s.Err())
+	})
+}
@@ -0,0 +1,447 @@
+// Package keyspace builds Redis types whose keys share a common prefix, such as
+// "service:tenant:users". It gives every service the same key layout without building
+// key strings by hand.
+package keyspace
+
+import (
+	"context"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/geo"
+	"github.com/MasterOfBinary/redistypes/hash"
+	"github.com/MasterOfBinary/redistypes/hyperloglog"
+	"github.com/MasterOfBinary/redistypes/list"
+	"github.com/MasterOfBinary/redistypes/set"
+	"github.com/MasterOfBinary/redistypes/sortedset"
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/MasterOfBinary/redistypes/strings"
+)
+
+// DefaultSeparator is the separator used by convention in Redis key names.
+const DefaultSeparator = ":"
+
+// Keyspace creates types whose keys start with its prefix. The key of a type called
+// name is the prefix, followed by the separator and name. Names given to the methods
+// of the types that create or rename keys, such as Rename and CloneTo, are relative
+// to the Keyspace as well, so those keys stay inside it.
+//
+// A Keyspace is immutable and safe for concurrent use.
+type Keyspace struct {
+	provider  redistypes.ConnProvider
+	prefix    string
+	separator string
+}
+
+// New creates a Keyspace with the given prefix and separator whose types borrow
+// connections from p. If prefix is empty, keys are not prefixed.
+func New(p redistypes.ConnProvider, prefix, separator string) *Keyspace {
+	return &Keyspace{
+		provider:  p,
+		prefix:    prefix,
+		separator: separator,
+	}
+}
+
+// Prefix returns the prefix of the keys in the Keyspace, not including the trailing
+// separator.
+func (k *Keyspace) Prefix() string {
+	return k.prefix
+}
+
+// Key returns the full Redis key for name.
+func (k *Keyspace) Key(name string) string {
+	if k.prefix == "" {
+		return name
+	}
+	return k.prefix + k.separator + name
+}
+
+// Sub returns a Keyspace nested inside k, whose prefix is the key for name in k.
+func (k *Keyspace) Sub(name string) *Keyspace {
+	return &Keyspace{
+		provider:  k.provider,
+		prefix:    k.Key(name),
+		separator: k.separator,
+	}
+}
+
+// Type returns a redistypes.Type for the key name in the Keyspace.
+func (k *Keyspace) Type(name string) redistypes.Type {
+	return k.wrapType(redistypes.NewRedisTypeFromProvider(k.provider, k.Key(name)))
+}
+
+// List returns a list.List for the key name in the Keyspace.
+func (k *Keyspace) List(name string) list.List {
+	return k.wrapList(list.NewRedisListFromProvider(k.provider, k.Key(name)))
+}
+
+// Set returns a set.Set for the key name in the Keyspace.
+func (k *Keyspace) Set(name string) set.Set {
+	return k.wrapSet(set.NewRedisSetFromProvider(k.provider, k.Key(name)))
+}
+
+// SortedSet returns a sortedset.SortedSet for the key name in the Keyspace.
+func (k *Keyspace) SortedSet(name string) sortedset.SortedSet {
+	return k.wrapSortedSet(sortedset.NewRedisSortedSetFromProvider(k.provider, k.Key(name)))
+}
+
+// Stream returns a stream.Stream for the key name in the Keyspace.
+func (k *Keyspace) Stream(name string) stream.Stream {
+	return k.wrapStream(stream.NewRedisStreamFromProvider(k.provider, k.Key(name)))
+}
+
+// Value returns a strings.Value for the key name in the Keyspace.
+func (k *Keyspace) Value(name string) strings.Value {
+	return k.wrapValue(strings.NewRedisValueFromProvider(k.provider, k.Key(name)))
+}
+
+// Hash returns a hash.Hash for the key name in the Keyspace.
+func (k *Keyspace) Hash(name string) hash.Hash {
+	return k.wrapHash(hash.NewRedisHashFromProvider(k.provider, k.Key(name)))
+}
+
+// HyperLogLog returns a hyperloglog.HyperLogLog for the key name in the Keyspace.
+func (k *Keyspace) HyperLogLog(name string) hyperloglog.HyperLogLog {
+	return k.wrapHyperLogLog(hyperloglog.NewRedisHyperLogLogFromProvider(k.provider, k.Key(name)))
+}
+
+// Geo returns a geo.Geo for the key name in the Keyspace.
+func (k *Keyspace) Geo(name string) geo.Geo {
+	return k.wrapGeo(geo.NewRedisGeoFromProvider(k.provider, k.Key(name)))
+}
+
+// Bitmap returns a bitmap.Bitmap for the key name in the Keyspace.
+func (k *Keyspace) Bitmap(name string) bitmap.Bitmap {
+	return k.wrapBitmap(bitmap.NewRedisBitmapFromProvider(k.provider, k.Key(name)))
+}
+
+func (k *Keyspace) wrapType(t redistypes.Type) redistypes.Type {
+	return &namespacedType{Type: t, keyspace: k}
+}
+
+func (k *Keyspace) wrapList(l list.List) list.List {
+	return &namespacedList{List: l, keyspace: k}
+}
+
+func (k *Keyspace) wrapSet(s set.Set) set.Set {
+	return &namespacedSet{Set: s, keyspace: k}
+}
+
+func (k *Keyspace) wrapSortedSet(s sortedset.SortedSet) sortedset.SortedSet {
+	return &namespacedSortedSet{SortedSet: s, keyspace: k}
+}
+
+func (k *Keyspace) wrapStream(s stream.Stream) stream.Stream {
+	return &namespacedStream{Stream: s, keyspace: k}
+}
+
+func (k *Keyspace) wrapValue(v strings.Value) strings.Value {
+	return &namespacedValue{Value: v, keyspace: k}
+}
+
+func (k *Keyspace) wrapHash(h hash.Hash) hash.Hash {
+	return &namespacedHash{Hash: h, keyspace: k}
+}
+
+func (k *Keyspace) wrapHyperLogLog(hll hyperloglog.HyperLogLog) hyperloglog.HyperLogLog {
+	return &namespacedHyperLogLog{HyperLogLog: hll, keyspace: k}
+}
+
+func (k *Keyspace) wrapGeo(g geo.Geo) geo.Geo {
+	return &namespacedGeo{Geo: g, keyspace: k}
+}
+
+func (k *Keyspace) wrapBitmap(b bitmap.Bitmap) bitmap.Bitmap {
+	return &namespacedBitmap{Bitmap: b, keyspace: k}
+}
+
+// namespacedType is a Type whose methods that take key names use names relative to
+// the Keyspace.
+type namespacedType struct {
+	redistypes.Type
+	keyspace *Keyspace
+}
+
+func (t *namespacedType) Copy(destination string, replace bool) (bool, error) {
+	return t.Type.Copy(t.keyspace.Key(destination), replace)
+}
+
+func (t *namespacedType) Rename(newkey string) error {
+	return t.Type.Rename(t.keyspace.Key(newkey))
+}
+
+func (t *namespacedType) RenameNX(newkey string) (bool, error) {
+	return t.Type.RenameNX(t.keyspace.Key(newkey))
+}
+
+func (t *namespacedType) WithContext(ctx context.Context) redistypes.Type {
+	return t.keyspace.wrapType(t.Type.WithContext(ctx))
+}
+
+type namespacedList struct {
+	list.List
+	keyspace *Keyspace
+}
+
+func (l *namespacedList) Base() redistypes.Type {
+	return l.keyspace.wrapType(l.List.Base())
+}
+
+func (l *namespacedList) CloneTo(name string) (list.List, error) {
+	clone, err := l.List.CloneTo(l.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return l.keyspace.wrapList(clone), nil
+}
+
+func (l *namespacedList) BlockingMultiPop(dir list.Direction, count int64, timeout time.Duration,
+	others ...list.List) ([]interface{}, list.List, error) {
+	values, source, err := l.List.BlockingMultiPop(dir, count, timeout, others...)
+	return values, l.source(source), err
+}
+
+func (l *namespacedList) MultiPop(dir list.Direction, count int64, others ...list.List) ([]interface{}, list.List, error) {
+	values, source, err := l.List.MultiPop(dir, count, others...)
+	return values, l.source(source), err
+}
+
+func (l *namespacedList) WithContext(ctx context.Context) list.List {
+	return l.keyspace.wrapList(l.List.WithContext(ctx))
+}
+
+// source returns l instead of the List it wraps when that is the source of a pop.
+func (l *namespacedList) source(s list.List) list.List {
+	if s == l.List {
+		return l
+	}
+	return s
+}
+
+type namespacedSet struct {
+	set.Set
+	keyspace *Keyspace
+}
+
+func (s *namespacedSet) Base() redistypes.Type {
+	return s.keyspace.wrapType(s.Set.Base())
+}
+
+func (s *namespacedSet) CloneTo(name string) (set.Set, error) {
+	clone, err := s.Set.CloneTo(s.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSet(clone), nil
+}
+
+func (s *namespacedSet) DiffStore(name string, others ...set.Set) (set.Set, error) {
+	diff, err := s.Set.DiffStore(s.keyspace.Key(name), others...)
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSet(diff), nil
+}
+
+func (s *namespacedSet) InterStore(name string, others ...set.Set) (set.Set, error) {
+	inter, err := s.Set.InterStore(s.keyspace.Key(name), others...)
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSet(inter), nil
+}
+
+func (s *namespacedSet) UnionStore(name string, others ...set.Set) (set.Set, error) {
+	union, err := s.Set.UnionStore(s.keyspace.Key(name), others...)
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSet(union), nil
+}
+
+func (s *namespacedSet) WithContext(ctx context.Context) set.Set {
+	return s.keyspace.wrapSet(s.Set.WithContext(ctx))
+}
+
+type namespacedSortedSet struct {
+	sortedset.SortedSet
+	keyspace *Keyspace
+}
+
+func (s *namespacedSortedSet) Base() redistypes.Type {
+	return s.keyspace.wrapType(s.SortedSet.Base())
+}
+
+func (s *namespacedSortedSet) CloneTo(name string) (sortedset.SortedSet, error) {
+	clone, err := s.SortedSet.CloneTo(s.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSortedSet(clone), nil
+}
+
+func (s *namespacedSortedSet) InterStore(name string, options sortedset.StoreOptions,
+	others ...sortedset.SortedSet) (sortedset.SortedSet, error) {
+	inter, err := s.SortedSet.InterStore(s.keyspace.Key(name), options, others...)
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSortedSet(inter), nil
+}
+
+func (s *namespacedSortedSet) UnionStore(name string, options sortedset.StoreOptions,
+	others ...sortedset.SortedSet) (sortedset.SortedSet, error) {
+	union, err := s.SortedSet.UnionStore(s.keyspace.Key(name), options, others...)
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapSortedSet(union), nil
+}
+
+func (s *namespacedSortedSet) WithContext(ctx context.Context) sortedset.SortedSet {
+	return s.keyspace.wrapSortedSet(s.SortedSet.WithContext(ctx))
+}
+
+type namespacedStream struct {
+	stream.Stream
+	keyspace *Keyspace
+}
+
+func (s *namespacedStream) Base() redistypes.Type {
+	return s.keyspace.wrapType(s.Stream.Base())
+}
+
+func (s *namespacedStream) CloneTo(name string) (stream.Stream, error) {
+	clone, err := s.Stream.CloneTo(s.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return s.keyspace.wrapStream(clone), nil
+}
+
+func (s *namespacedStream) WithContext(ctx context.Context) stream.Stream {
+	return s.keyspace.wrapStream(s.Stream.WithContext(ctx))
+}
+
+type namespacedValue struct {
+	strings.Value
+	keyspace *Keyspace
+}
+
+func (v *namespacedValue) Base() redistypes.Type {
+	return v.keyspace.wrapType(v.Value.Base())
+}
+
+func (v *namespacedValue) CloneTo(name string) (strings.Value, error) {
+	clone, err := v.Value.CloneTo(v.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return v.keyspace.wrapValue(clone), nil
+}
+
+func (v *namespacedValue) WithContext(ctx context.Context) strings.Value {
+	return v.keyspace.wrapValue(v.Value.WithContext(ctx))
+}
+
+type namespacedHash struct {
+	hash.Hash
+	keyspace *Keyspace
+}
+
+func (h *namespacedHash) Base() redistypes.Type {
+	return h.keyspace.wrapType(h.Hash.Base())
+}
+
+func (h *namespacedHash) CloneTo(name string) (hash.Hash, error) {
+	clone, err := h.Hash.CloneTo(h.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return h.keyspace.wrapHash(clone), nil
+}
+
+func (h *namespacedHash) WithContext(ctx context.Context) hash.Hash {
+	return h.keyspace.wrapHash(h.Hash.WithContext(ctx))
+}
+
+type namespacedHyperLogLog struct {
+	hyperloglog.HyperLogLog
+	keyspace *Keyspace
+}
+
+func (h *namespacedHyperLogLog) Base() redistypes.Type {
+	return h.keyspace.wrapType(h.HyperLogLog.Base())
+}
+
+func (h *namespacedHyperLogLog) CloneTo(name string) (hyperloglog.HyperLogLog, error) {
+	clone, err := h.HyperLogLog.CloneTo(h.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return h.keyspace.wrapHyperLogLog(clone), nil
+}
+
+func (h *namespacedHyperLogLog) Merge(name string, other hyperloglog.HyperLogLog) (hyperloglog.HyperLogLog, error) {
+	merged, err := h.HyperLogLog.Merge(h.keyspace.Key(name), other)
+	if err != nil {
+		return nil, err
+	}
+	return h.keyspace.wrapHyperLogLog(merged), nil
+}
+
+func (h *namespacedHyperLogLog) WithContext(ctx context.Context) hyperloglog.HyperLogLog {
+	return h.keyspace.wrapHyperLogLog(h.HyperLogLog.WithContext(ctx))
+}
+
+type namespacedGeo struct {
+	geo.Geo
+	keyspace *Keyspace
+}
+
+func (g *namespacedGeo) Base() redistypes.Type {
+	return g.keyspace.wrapType(g.Geo.Base())
+}
+
+func (g *namespacedGeo) CloneTo(name string) (geo.Geo, error) {
+	clone, err := g.Geo.CloneTo(g.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return g.keyspace.wrapGeo(clone), nil
+}
+
+func (g *namespacedGeo) SearchStore(name string, query geo.SearchQuery, options geo.SearchOptions) (geo.Geo, error) {
+	store, err := g.Geo.SearchStore(g.keyspace.Key(name), query, options)
+	if err != nil {
+		return nil, err
+	}
+	return g.keyspace.wrapGeo(store), nil
+}
+
+func (g *namespacedGeo) WithContext(ctx context.Context) geo.Geo {
+	return g.keyspace.wrapGeo(g.Geo.WithContext(ctx))
+}
+
+type namespacedBitmap struct {
+	bitmap.Bitmap
+	keyspace *Keyspace
+}
+
+func (b *namespacedBitmap) Base() redistypes.Type {
+	return b.keyspace.wrapType(b.Bitmap.Base())
+}
+
+func (b *namespacedBitmap) CloneTo(name string) (bitmap.Bitmap, error) {
+	clone, err := b.Bitmap.CloneTo(b.keyspace.Key(name))
+	if err != nil {
+		return nil, err
+	}
+	return b.keyspace.wrapBitmap(clone), nil
+}
+
+func (b *namespacedBitmap) WithContext(ctx context.Context) bitmap.Bitmap {
+	return b.keyspace.wrapBitmap(b.Bitmap.WithContext(ctx))
+}
@@ -0,0 +1,315 @@
+package keyspace_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/geo"
+	"github.com/MasterOfBinary/redistypes/hyperloglog"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/keyspace"
+	"github.com/MasterOfBinary/redistypes/list"
+	"github.com/MasterOfBinary/redistypes/sortedset"
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/MasterOfBinary/redistypes/strings"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func ExampleKeyspace() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	users := keyspace.New(redistypes.SingleConn(conn), "myapp", keyspace.DefaultSeparator).Sub("users")
+
+	visitors := users.HyperLogLog("visitors")
+	defer visitors.Base().Delete()
+
+	fmt.Println(visitors.Base().Name())
+
+	// Output:
+	// myapp:users:visitors
+}
+
+func TestKeyspace_Key(t *testing.T) {
+	p := redistypes.SingleConn(conn)
+
+	t.Run("empty prefix", func(t *testing.T) {
+		ks := keyspace.New(p, "", ":")
+		assert.Equal(t, "key", ks.Key("key"))
+		assert.Equal(t, "sub:key", ks.Sub("sub").Key("key"))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		ks := keyspace.New(p, "app", "/")
+		assert.Equal(t, "app", ks.Prefix())
+		assert.Equal(t, "app/key", ks.Key("key"))
+		assert.Equal(t, "app/a/b", ks.Sub("a").Sub("b").Prefix())
+		assert.Equal(t, "app/a/b/key", ks.Sub("a").Sub("b").Key("key"))
+	})
+}
+
+func TestKeyspace_Type(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	r := ks.Type("key")
+	defer r.Delete()
+	assert.Equal(t, ks.Key("key"), r.Name())
+
+	_, _ = conn.Do("SET", r.Name(), "value")
+
+	t.Run("Rename", func(t *testing.T) {
+		err := r.Rename("renamed")
+		assert.Nil(t, err)
+		assert.Equal(t, ks.Key("renamed"), r.Name())
+	})
+
+	t.Run("RenameNX", func(t *testing.T) {
+		value, err := r.RenameNX("renamednx")
+		assert.Nil(t, err)
+		assert.True(t, value)
+		assert.Equal(t, ks.Key("renamednx"), r.Name())
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		defer test.DeleteKey(ks.Key("copy"), conn)
+
+		value, err := r.Copy("copy", false)
+		assert.Nil(t, err)
+		assert.True(t, value)
+
+		value, err = redis.Bool(conn.Do("EXISTS", ks.Key("copy")))
+		assert.Nil(t, err)
+		assert.True(t, value)
+	})
+}
+
+func TestKeyspace_List(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	l := ks.List("list")
+	defer l.Base().Delete()
+	assert.Equal(t, ks.Key("list"), l.Base().Name())
+
+	_, _ = l.RightPush(1, 2, 3)
+
+	clone, err := l.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+
+	values, source, err := l.MultiPop(list.Left, 1, clone)
+	assert.Nil(t, err)
+	assert.Len(t, values, 1)
+	assert.Equal(t, l, source)
+
+	err = l.Base().Rename("renamed")
+	assert.Nil(t, err)
+	assert.Equal(t, ks.Key("renamed"), l.Base().Name())
+}
+
+func TestKeyspace_Set(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	s := ks.Set("set")
+	defer s.Base().Delete()
+	assert.Equal(t, ks.Key("set"), s.Base().Name())
+
+	_, _ = s.Add(1, 2, 3)
+
+	clone, err := s.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+
+	value, err := clone.Card()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, value)
+
+	union, err := s.UnionStore("union", clone)
+	assert.Nil(t, err)
+	defer union.Base().Delete()
+	assert.Equal(t, ks.Key("union"), union.Base().Name())
+
+	inter, err := s.InterStore("inter", union)
+	assert.Nil(t, err)
+	defer inter.Base().Delete()
+	assert.Equal(t, ks.Key("inter"), inter.Base().Name())
+
+	diff, err := s.DiffStore("diff", inter)
+	assert.Nil(t, err)
+	defer diff.Base().Delete()
+	assert.Equal(t, ks.Key("diff"), diff.Base().Name())
+}
+
+func TestKeyspace_SortedSet(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	s := ks.SortedSet("zset")
+	defer s.Base().Delete()
+	assert.Equal(t, ks.Key("zset"), s.Base().Name())
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}})
+
+	union, err := s.UnionStore("union", sortedset.StoreOptions{})
+	assert.Nil(t, err)
+	defer union.Base().Delete()
+	assert.Equal(t, ks.Key("union"), union.Base().Name())
+
+	inter, err := s.InterStore("inter", sortedset.StoreOptions{}, union)
+	assert.Nil(t, err)
+	defer inter.Base().Delete()
+	assert.Equal(t, ks.Key("inter"), inter.Base().Name())
+}
+
+func TestKeyspace_Stream(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	s := ks.Stream("stream")
+	defer s.Base().Delete()
+	assert.Equal(t, ks.Key("stream"), s.Base().Name())
+
+	_, _ = s.Add(map[string]interface{}{"a": 1}, stream.AddOptions{})
+
+	clone, err := s.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+}
+
+func TestKeyspace_Value(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	v := ks.Value("value")
+	defer v.Base().Delete()
+	assert.Equal(t, ks.Key("value"), v.Base().Name())
+
+	other := ks.Value("other")
+	defer other.Base().Delete()
+
+	err := strings.MultiSet(context.Background(), redistypes.SingleConn(conn), []strings.Value{v, other}, []interface{}{"a", "b"})
+	assert.Nil(t, err)
+
+	clone, err := v.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+
+	values, err := redis.Strings(strings.MultiGet(context.Background(), redistypes.SingleConn(conn), clone, other))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, values)
+}
+
+func TestKeyspace_Hash(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	h := ks.Hash("hash")
+	defer h.Base().Delete()
+	assert.Equal(t, ks.Key("hash"), h.Base().Name())
+
+	_, _ = h.Set("a", 1)
+
+	clone, err := h.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+}
+
+func TestKeyspace_HyperLogLog(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	hll := ks.HyperLogLog("hll")
+	defer hll.Base().Delete()
+	assert.Equal(t, ks.Key("hll"), hll.Base().Name())
+
+	other := hyperloglog.NewRedisHyperLogLog(conn, test.RandomKey())
+	defer other.Base().Delete()
+
+	_, _ = hll.Add(1, 2)
+	_, _ = other.Add(3)
+
+	merged, err := hll.Merge("merged", other)
+	assert.Nil(t, err)
+	defer merged.Base().Delete()
+	assert.Equal(t, ks.Key("merged"), merged.Base().Name())
+
+	value, err := merged.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, value)
+
+	clone, err := merged.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+}
+
+func TestKeyspace_Geo(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	g := ks.Geo("geo")
+	defer g.Base().Delete()
+	assert.Equal(t, ks.Key("geo"), g.Base().Name())
+
+	_, _ = g.Add([]geo.Location{{Member: "a", Position: geo.Position{Longitude: 1, Latitude: 1}}})
+
+	store, err := g.SearchStore("store", geo.SearchQuery{FromMember: "a", Radius: 1}, geo.SearchOptions{})
+	assert.Nil(t, err)
+	defer store.Base().Delete()
+	assert.Equal(t, ks.Key("store"), store.Base().Name())
+
+	positions, err := store.Pos("a")
+	assert.Nil(t, err)
+	assert.Len(t, positions, 1)
+
+	clone, err := store.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+}
+
+func TestKeyspace_Bitmap(t *testing.T) {
+	ks := keyspace.New(redistypes.SingleConn(conn), test.RandomKey(), keyspace.DefaultSeparator)
+
+	b := ks.Bitmap("bitmap")
+	defer b.Base().Delete()
+	assert.Equal(t, ks.Key("bitmap"), b.Base().Name())
+
+	dest := ks.Bitmap("dest")
+	defer dest.Base().Delete()
+
+	_, _ = b.SetBit(1, true)
+
+	_, err := b.BitOp(bitmap.Not, dest)
+	assert.Nil(t, err)
+
+	value, err := dest.Count()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 7, value)
+
+	clone, err := dest.CloneTo("clone")
+	assert.Nil(t, err)
+	defer clone.Base().Delete()
+	assert.Equal(t, ks.Key("clone"), clone.Base().Name())
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
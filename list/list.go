@@ -3,6 +3,7 @@
 package list
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -18,6 +19,30 @@ const (
 	After            = "AFTER"
 )
 
+// Direction is an end of a list, used by the commands that pop from or push to either
+// end.
+type Direction string
+
+const (
+	// Left is the start of the list, where LPUSH pushes values.
+	Left Direction = "LEFT"
+
+	// Right is the end of the list, where RPUSH pushes values.
+	Right Direction = "RIGHT"
+)
+
+// PosOptions contains the options of the Redis command LPOS.
+type PosOptions struct {
+	// Rank selects which match is returned, starting at 1 for the first. A negative
+	// rank searches from the end of the list, so -1 is the last match. If Rank is 0,
+	// the first match is returned.
+	Rank int64
+
+	// MaxLen limits the search to the first MaxLen values of the list, or the last
+	// ones if Rank is negative. If MaxLen is 0, the whole list is searched.
+	MaxLen uint64
+}
+
 // List is a Redis implementation of a linked list.
 type List interface {
 	// Base returns the base Type.
@@ -34,6 +59,29 @@ type List interface {
 	// See https://redis.io/commands/blpop.
 	BlockingLeftPop(timeout time.Duration) (interface{}, error)
 
+	// BlockingMove implements the Redis command BLMOVE. It works like Move but it blocks
+	// until an element exists in the list or timeout is reached. If the timeout is
+	// reached, nil is returned. A timeout of 0 can be used to block indefinitely.
+	//
+	// Since Redis specifies timeout to be in seconds, millisecond-level precision is
+	// not possible. If the timeout is not a multiple of one second, an error will be
+	// returned.
+	//
+	// See https://redis.io/commands/blmove.
+	BlockingMove(destination List, from, to Direction, timeout time.Duration) (interface{}, error)
+
+	// BlockingMultiPop implements the Redis command BLMPOP. It works like MultiPop but
+	// it blocks until an element exists in one of the lists or timeout is reached. If
+	// the timeout is reached, no values and a nil List are returned. A timeout of 0 can
+	// be used to block indefinitely.
+	//
+	// Since Redis specifies timeout to be in seconds, millisecond-level precision is
+	// not possible. If the timeout is not a multiple of one second, an error will be
+	// returned.
+	//
+	// See https://redis.io/commands/blmpop.
+	BlockingMultiPop(dir Direction, count int64, timeout time.Duration, others ...List) ([]interface{}, List, error)
+
 	// BlockingRightPop implements the Redis command BRPOP. It works like RPOP but it
 	// blocks until an element exists in the list or timeout is reached. If the timeout
 	// is reached, nil is returned. A timeout of 0 can be used to block indefinitely.
@@ -54,8 +102,18 @@ type List interface {
 	// returned.
 	//
 	// See https://redis.io/commands/brpoplpush.
+	//
+	// Deprecated: Redis 6.2 replaces BRPOPLPUSH with BLMOVE, so use BlockingMove with
+	// Right and Left instead.
 	BlockingRightPopLeftPush(destination List, timeout time.Duration) (interface{}, error)
 
+	// CloneTo copies the list to a new key called name using the Redis command COPY,
+	// and returns a List for the copy. If name already exists, it is overwritten. If
+	// the list does not exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (List, error)
+
 	// Index implements the Redis command LINDEX. It returns the value at index in
 	// the list. The index is 0-based, with the first index 0. Negative numbers
 	// denote indices starting at the end of the list, as described by the documentation.
@@ -97,6 +155,36 @@ type List interface {
 	// See https://redis.io/commands/llen.
 	Length() (uint64, error)
 
+	// Move implements the Redis command LMOVE. It pops a value from the from end of the
+	// list, pushes it onto the to end of destination and returns it. If the list is
+	// empty, nil is returned. destination can be the list itself, which rotates it if
+	// from and to are different.
+	//
+	// See https://redis.io/commands/lmove.
+	Move(destination List, from, to Direction) (interface{}, error)
+
+	// MultiPop implements the Redis command LMPOP. It pops up to count values from the
+	// dir end of the first non-empty list out of the list and others, in that order. It
+	// returns the values and the List they were popped from, which is one of the
+	// handles passed in. If all of the lists are empty, no values and a nil List are
+	// returned. If count is 0, one value is popped.
+	//
+	// See https://redis.io/commands/lmpop.
+	MultiPop(dir Direction, count int64, others ...List) ([]interface{}, List, error)
+
+	// Pos implements the Redis command LPOS. It returns the index of the value in the
+	// list, selected using options, or -1 if it isn't found.
+	//
+	// See https://redis.io/commands/lpos.
+	Pos(value interface{}, options PosOptions) (int64, error)
+
+	// Positions implements the Redis command LPOS with COUNT. It works like Pos, but it
+	// returns the indices of up to count matches, starting with the match selected by
+	// options.Rank. If count is 0, all of the matches are returned.
+	//
+	// See https://redis.io/commands/lpos.
+	Positions(value interface{}, count uint64, options PosOptions) ([]int64, error)
+
 	// Range implements the Redis command LRANGE. It returns a range of values in the
 	// list, starting at index start and ending at index stop. If end is negative, it
 	// returns all values from start to the end of the list.
@@ -125,6 +213,9 @@ type List interface {
 	// right of the list and pushes it on the left of destination.
 	//
 	// See https://redis.io/commands/rpoplpush.
+	//
+	// Deprecated: Redis 6.2 replaces RPOPLPUSH with LMOVE, so use Move with Right and
+	// Left instead.
 	RightPopLeftPush(destination List) (interface{}, error)
 
 	// RightPush implements the Redis command RPUSH. It pushes one or more values onto
@@ -153,19 +244,39 @@ type List interface {
 	//
 	// See https://redis.io/commands/ltrim.
 	Trim(start, stop int64) error
+
+	// WithContext returns a copy of the List that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	//
+	// The blocking commands can be cancelled with ctx while they are blocked, and leave
+	// the connection usable. If the List was created from a provider that hands out a
+	// separate connection for each command, like *redis.Pool, they are interrupted with
+	// CLIENT UNBLOCK as soon as ctx is done. Otherwise they block one second at a time
+	// and notice cancellation within about one second.
+	WithContext(ctx context.Context) List
 }
 
 type redisList struct {
-	conn redis.Conn
-	base redistypes.Type
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
 }
 
 // NewRedisList creates a Redis implementation of List given redigo connection conn and name. The
 // Redis key used to identify the List will be name.
 func NewRedisList(conn redis.Conn, name string) List {
+	return NewRedisListFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisListFromProvider creates a Redis implementation of List given ConnProvider p and name.
+// Each command borrows a connection from p. The Redis key used to identify the List
+// will be name.
+func NewRedisListFromProvider(p redistypes.ConnProvider, name string) List {
 	return &redisList{
-		conn: conn,
-		base: redistypes.NewRedisType(conn, name),
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
 	}
 }
 
@@ -179,7 +290,9 @@ func (r *redisList) BlockingLeftPop(timeout time.Duration) (interface{}, error)
 		return nil, errors.New("Duration is not a multiple of one second")
 	}
 
-	values, err := redis.Values(r.conn.Do("BLPOP", r.Base().Name(), seconds))
+	values, err := redis.Values(internal.BlockingDo(r.ctx, r.provider, timeout, "BLPOP", func(seconds int64) []interface{} {
+		return []interface{}{r.Base().Name(), seconds}
+	}))
 	if err != nil {
 		return nil, err
 	} else if len(values) != 2 {
@@ -188,13 +301,39 @@ func (r *redisList) BlockingLeftPop(timeout time.Duration) (interface{}, error)
 	return values[1], err
 }
 
+func (r *redisList) BlockingMove(destination List, from, to Direction, timeout time.Duration) (interface{}, error) {
+	seconds := int64(timeout.Seconds())
+	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return nil, errors.New("Duration is not a multiple of one second")
+	}
+
+	return internal.BlockingDo(r.ctx, r.provider, timeout, "BLMOVE", func(seconds int64) []interface{} {
+		return []interface{}{r.Base().Name(), destination.Base().Name(), string(from), string(to), seconds}
+	})
+}
+
+func (r *redisList) BlockingMultiPop(dir Direction, count int64, timeout time.Duration, others ...List) ([]interface{}, List, error) {
+	seconds := int64(timeout.Seconds())
+	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return nil, nil, errors.New("Duration is not a multiple of one second")
+	}
+
+	lists := append([]List{r}, others...)
+	reply, err := internal.BlockingDo(r.ctx, r.provider, timeout, "BLMPOP", func(seconds int64) []interface{} {
+		return append([]interface{}{seconds}, multiPopArgs(dir, count, lists)...)
+	})
+	return multiPopReply(reply, err, lists)
+}
+
 func (r *redisList) BlockingRightPop(timeout time.Duration) (interface{}, error) {
 	seconds := int64(timeout.Seconds())
 	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
 		return nil, errors.New("Duration is not a multiple of one second")
 	}
 
-	values, err := redis.Values(r.conn.Do("BRPOP", r.Base().Name(), seconds))
+	values, err := redis.Values(internal.BlockingDo(r.ctx, r.provider, timeout, "BRPOP", func(seconds int64) []interface{} {
+		return []interface{}{r.Base().Name(), seconds}
+	}))
 	if err != nil {
 		return nil, err
 	} else if len(values) != 2 {
@@ -209,7 +348,9 @@ func (r *redisList) BlockingRightPopLeftPush(destination List, timeout time.Dura
 		return nil, errors.New("Duration is not a multiple of one second")
 	}
 
-	value, err := r.conn.Do("BRPOPLPUSH", r.Base().Name(), destination.Base().Name(), seconds)
+	value, err := internal.BlockingDo(r.ctx, r.provider, timeout, "BRPOPLPUSH", func(seconds int64) []interface{} {
+		return []interface{}{r.Base().Name(), destination.Base().Name(), seconds}
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -217,62 +358,232 @@ func (r *redisList) BlockingRightPopLeftPush(destination List, timeout time.Dura
 	return value, err
 }
 
+func (r *redisList) CloneTo(name string) (List, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisListFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
 func (r *redisList) Index(index int64) (interface{}, error) {
-	return r.conn.Do("LINDEX", r.Base().Name(), index)
+	return internal.Do(r.ctx, r.provider, "LINDEX", r.Base().Name(), index)
 }
 
 func (r *redisList) Insert(adj Adjacency, pivot interface{}, value interface{}) (int64, error) {
-	return redis.Int64(r.conn.Do("LINSERT", r.Base().Name(), string(adj), pivot, value))
+	return redis.Int64(internal.Do(r.ctx, r.provider, "LINSERT", r.Base().Name(), string(adj), pivot, value))
 }
 
 func (r *redisList) LeftPop() (interface{}, error) {
-	return r.conn.Do("LPOP", r.Base().Name())
+	return internal.Do(r.ctx, r.provider, "LPOP", r.Base().Name())
 }
 
 func (r *redisList) LeftPush(args ...interface{}) (uint64, error) {
 	args = internal.PrependInterface(r.Base().Name(), args...)
-	return redis.Uint64(r.conn.Do("LPUSH", args...))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "LPUSH", args...))
 }
 
 func (r *redisList) LeftPushX(arg interface{}) (uint64, error) {
-	return redis.Uint64(r.conn.Do("LPUSHX", r.Base().Name(), arg))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "LPUSHX", r.Base().Name(), arg))
 }
 
 func (r *redisList) Length() (uint64, error) {
-	return redis.Uint64(r.conn.Do("LLEN", r.Base().Name()))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "LLEN", r.Base().Name()))
+}
+
+func (r *redisList) Move(destination List, from, to Direction) (interface{}, error) {
+	return internal.Do(r.ctx, r.provider, "LMOVE", r.Base().Name(), destination.Base().Name(), string(from), string(to))
+}
+
+func (r *redisList) MultiPop(dir Direction, count int64, others ...List) ([]interface{}, List, error) {
+	lists := append([]List{r}, others...)
+	reply, err := internal.Do(r.ctx, r.provider, "LMPOP", multiPopArgs(dir, count, lists)...)
+	return multiPopReply(reply, err, lists)
+}
+
+func (r *redisList) Pos(value interface{}, options PosOptions) (int64, error) {
+	args := append([]interface{}{r.Base().Name(), value}, options.args()...)
+	return posReply(internal.Do(r.ctx, r.provider, "LPOS", args...))
+}
+
+func (r *redisList) Positions(value interface{}, count uint64, options PosOptions) ([]int64, error) {
+	return redis.Int64s(internal.Do(r.ctx, r.provider, "LPOS", positionsArgs(r.Base().Name(), value, count, options)...))
 }
 
 func (r *redisList) Range(start, stop int64) ([]interface{}, error) {
-	return redis.Values(r.conn.Do("LRANGE", r.Base().Name(), start, stop))
+	return redis.Values(internal.Do(r.ctx, r.provider, "LRANGE", r.Base().Name(), start, stop))
 }
 
 func (r *redisList) Remove(count int64, value interface{}) (uint64, error) {
-	return redis.Uint64(r.conn.Do("LREM", r.Base().Name(), count, value))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "LREM", r.Base().Name(), count, value))
 }
 
 func (r *redisList) RightPopLeftPush(destination List) (interface{}, error) {
-	return r.conn.Do("RPOPLPUSH", r.Base().Name(), destination.Base().Name())
+	return internal.Do(r.ctx, r.provider, "RPOPLPUSH", r.Base().Name(), destination.Base().Name())
 }
 
 func (r *redisList) RightPop() (interface{}, error) {
-	return r.conn.Do("RPOP", r.Base().Name())
+	return internal.Do(r.ctx, r.provider, "RPOP", r.Base().Name())
 }
 
 func (r *redisList) RightPush(args ...interface{}) (uint64, error) {
 	args = internal.PrependInterface(r.Base().Name(), args...)
-	return redis.Uint64(r.conn.Do("RPUSH", args...))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "RPUSH", args...))
 }
 
 func (r *redisList) RightPushX(arg interface{}) (uint64, error) {
-	return redis.Uint64(r.conn.Do("RPUSHX", r.Base().Name(), arg))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "RPUSHX", r.Base().Name(), arg))
 }
 
 func (r *redisList) Set(index int64, value interface{}) error {
-	_, err := r.conn.Do("LSET", r.Base().Name(), index, value)
+	_, err := internal.Do(r.ctx, r.provider, "LSET", r.Base().Name(), index, value)
 	return err
 }
 
 func (r *redisList) Trim(start, stop int64) error {
-	_, err := r.conn.Do("LTRIM", r.Base().Name(), start, stop)
+	_, err := internal.Do(r.ctx, r.provider, "LTRIM", r.Base().Name(), start, stop)
 	return err
 }
+
+func (r *redisList) WithContext(ctx context.Context) List {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisList{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// BlockingPop implements the Redis commands BLPOP and BRPOP with several lists. It
+// borrows a connection from p and blocks until one of lists is non-empty or timeout is
+// reached, then pops a value from the dir end of the first non-empty list, in the order
+// given. It returns the value and the List it was popped from, which is one of lists.
+// If the timeout is reached, a nil value and List are returned. A timeout of 0 can be
+// used to block indefinitely.
+//
+// Since Redis specifies timeout to be in seconds, millisecond-level precision is not
+// possible. If the timeout is not a multiple of one second, an error will be returned.
+//
+// See https://redis.io/commands/blpop and https://redis.io/commands/brpop.
+func BlockingPop(ctx context.Context, p redistypes.ConnProvider, timeout time.Duration, dir Direction,
+	lists ...List) (interface{}, List, error) {
+	if len(lists) == 0 {
+		return nil, nil, errors.New("No lists given")
+	}
+
+	seconds := int64(timeout.Seconds())
+	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return nil, nil, errors.New("Duration is not a multiple of one second")
+	}
+
+	var cmd string
+	switch dir {
+	case Left:
+		cmd = "BLPOP"
+	case Right:
+		cmd = "BRPOP"
+	default:
+		return nil, nil, errors.New("Unknown direction")
+	}
+
+	values, err := redis.Values(internal.BlockingDo(ctx, p, timeout, cmd, func(seconds int64) []interface{} {
+		args := make([]interface{}, 0, len(lists)+1)
+		for _, l := range lists {
+			args = append(args, l.Base().Name())
+		}
+		return append(args, seconds)
+	}))
+	if err == redis.ErrNil {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	} else if len(values) != 2 {
+		return nil, nil, errors.New("Unexpected response length")
+	}
+
+	name, err := redis.String(values[0], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return values[1], findList(name, lists), nil
+}
+
+// args returns the arguments to LPOS for the options.
+func (o PosOptions) args() []interface{} {
+	var args []interface{}
+	if o.Rank != 0 {
+		args = append(args, "RANK", o.Rank)
+	}
+	if o.MaxLen > 0 {
+		args = append(args, "MAXLEN", o.MaxLen)
+	}
+	return args
+}
+
+// posReply converts the reply of LPOS without COUNT to an index, or -1 if the value
+// wasn't found.
+func posReply(reply interface{}, err error) (int64, error) {
+	pos, err := redis.Int64(reply, err)
+	if err == redis.ErrNil {
+		return -1, nil
+	}
+	return pos, err
+}
+
+// positionsArgs returns the arguments to LPOS with COUNT.
+func positionsArgs(name string, value interface{}, count uint64, options PosOptions) []interface{} {
+	return append([]interface{}{name, value, "COUNT", count}, options.args()...)
+}
+
+// multiPopArgs returns the arguments to LMPOP for lists, which are also the arguments
+// to BLMPOP after the timeout.
+func multiPopArgs(dir Direction, count int64, lists []List) []interface{} {
+	args := []interface{}{len(lists)}
+	for _, l := range lists {
+		args = append(args, l.Base().Name())
+	}
+	args = append(args, string(dir))
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	return args
+}
+
+// multiPopReply converts the reply of LMPOP or BLMPOP to the values that were popped
+// and the one of lists they came from.
+func multiPopReply(reply interface{}, err error, lists []List) ([]interface{}, List, error) {
+	values, err := redis.Values(reply, err)
+	if err == redis.ErrNil {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	} else if len(values) != 2 {
+		return nil, nil, errors.New("Unexpected response length")
+	}
+
+	name, err := redis.String(values[0], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	popped, err := redis.Values(values[1], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return popped, findList(name, lists), nil
+}
+
+// findList returns the one of lists whose key is name, or nil if there isn't one.
+func findList(name string, lists []List) List {
+	for _, l := range lists {
+		if l.Base().Name() == name {
+			return l
+		}
+	}
+	return nil
+}
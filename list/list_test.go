@@ -1,6 +1,7 @@
 package list_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/MasterOfBinary/redistypes"
 	"github.com/MasterOfBinary/redistypes/internal"
 	"github.com/MasterOfBinary/redistypes/internal/test"
 	"github.com/MasterOfBinary/redistypes/list"
@@ -77,6 +79,34 @@ func ExampleList_Range() {
 	// [hello world]
 }
 
+func TestNewRedisListFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	l := list.NewRedisListFromProvider(pool, test.RandomKey())
+	defer l.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := l.RightPush(i)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	length, err := l.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, length)
+}
+
 func TestRedisList_BlockingLeftPop(t *testing.T) {
 	l := list.NewRedisList(conn, test.RandomKey())
 	defer l.Base().Delete()
@@ -84,6 +114,123 @@ func TestRedisList_BlockingLeftPop(t *testing.T) {
 	blockingPopTest(t, l, leftBlockingPop)
 }
 
+func TestRedisList_BlockingMove(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	l2 := list.NewRedisList(conn, test.RandomKey())
+	defer l2.Base().Delete()
+
+	t.Run("timeout", func(t *testing.T) {
+		value, err := l.BlockingMove(l2, list.Left, list.Right, time.Second)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := l.BlockingMove(l2, list.Left, list.Right, 1500*time.Millisecond)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("blocking test", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+
+			conn2 := redis.NewConn(netConn, 5*time.Second, 5*time.Second)
+			defer conn2.Close()
+
+			l1 := list.NewRedisList(conn2, l.Base().Name())
+
+			value, err := l1.BlockingMove(l2, list.Left, list.Right, 2*time.Second)
+
+			assert.Nil(t, err)
+			test.AssertEqual(t, 1, value)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(200 * time.Millisecond)
+			_, err := l.RightPush(1, 2, 3)
+			assert.Nil(t, err)
+		}()
+
+		wg.Wait()
+
+		values, _ := redis.Ints(l2.Range(0, -1))
+		assert.Equal(t, []int{1}, values)
+	})
+}
+
+func TestRedisList_BlockingMultiPop(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	l2 := list.NewRedisList(conn, test.RandomKey())
+	defer l2.Base().Delete()
+
+	t.Run("timeout", func(t *testing.T) {
+		values, source, err := l.BlockingMultiPop(list.Left, 2, time.Second, l2)
+		assert.Nil(t, err)
+		assert.Nil(t, values)
+		assert.Nil(t, source)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, _, err := l.BlockingMultiPop(list.Left, 2, 1500*time.Millisecond, l2)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("non-empty list", func(t *testing.T) {
+		_, _ = l2.RightPush(1, 2, 3)
+		values, source, err := l.BlockingMultiPop(list.Right, 2, time.Second, l2)
+		assert.Nil(t, err)
+		assert.Equal(t, l2, source)
+
+		ints, _ := redis.Ints(values, nil)
+		assert.Equal(t, []int{3, 2}, ints)
+	})
+
+	t.Run("blocking test", func(t *testing.T) {
+		_, _ = l2.Base().Delete()
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+
+			conn2 := redis.NewConn(netConn, 5*time.Second, 5*time.Second)
+			defer conn2.Close()
+
+			l1 := list.NewRedisList(conn2, l.Base().Name())
+			other := list.NewRedisList(conn2, l2.Base().Name())
+
+			values, source, err := l1.BlockingMultiPop(list.Left, 0, 2*time.Second, other)
+
+			assert.Nil(t, err)
+			assert.Equal(t, other, source)
+			ints, _ := redis.Ints(values, nil)
+			assert.Equal(t, []int{1}, ints)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(200 * time.Millisecond)
+			_, err := l2.RightPush(1, 2, 3)
+			assert.Nil(t, err)
+		}()
+
+		wg.Wait()
+	})
+}
+
 func TestRedisList_BlockingRightPop(t *testing.T) {
 	l := list.NewRedisList(conn, test.RandomKey())
 	defer l.Base().Delete()
@@ -220,6 +367,45 @@ func TestRedisList_BlockingRightPopLeftPush(t *testing.T) {
 	})
 }
 
+func TestRedisList_CloneTo(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		name := test.RandomKey()
+		_, err := l.CloneTo(name)
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("list with several items", func(t *testing.T) {
+		_, _ = l.RightPush("abc", "def")
+
+		clone, err := l.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = l.RightPush("ghi")
+
+		values, err := redis.Strings(clone.Range(0, -1))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"abc", "def"}, values)
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		l2 := list.NewRedisList(conn, test.RandomKey())
+		defer l2.Base().Delete()
+
+		_, _ = l2.RightPush("xyz")
+
+		clone, err := l.CloneTo(l2.Base().Name())
+		assert.Nil(t, err)
+
+		values, err := redis.Strings(clone.Range(0, -1))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"abc", "def", "ghi"}, values)
+	})
+}
+
 func TestRedisList_Index(t *testing.T) {
 	l := list.NewRedisList(conn, test.RandomKey())
 	defer l.Base().Delete()
@@ -433,6 +619,154 @@ func TestRedisList_Length(t *testing.T) {
 	})
 }
 
+func TestRedisList_Move(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	l2 := list.NewRedisList(conn, test.RandomKey())
+	defer l2.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := l.Move(l2, list.Left, list.Right)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("left to right", func(t *testing.T) {
+		_, _ = l.RightPush(1, 2, 3)
+		_, _ = l2.RightPush(4)
+		value, err := redis.Int(l.Move(l2, list.Left, list.Right))
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+
+		values, _ := redis.Ints(l2.Range(0, -1))
+		assert.Equal(t, []int{4, 1}, values)
+	})
+
+	t.Run("same list", func(t *testing.T) {
+		_, _ = l.Base().Delete()
+		_, _ = l.RightPush(1, 2, 3)
+		value, err := redis.Int(l.Move(l, list.Right, list.Left))
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, value)
+
+		values, _ := redis.Ints(l.Range(0, -1))
+		assert.Equal(t, []int{3, 1, 2}, values)
+	})
+}
+
+func TestRedisList_MultiPop(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	l2 := list.NewRedisList(conn, test.RandomKey())
+	defer l2.Base().Delete()
+
+	t.Run("non-existing keys", func(t *testing.T) {
+		values, source, err := l.MultiPop(list.Left, 2, l2)
+		assert.Nil(t, err)
+		assert.Nil(t, values)
+		assert.Nil(t, source)
+	})
+
+	t.Run("first list", func(t *testing.T) {
+		_, _ = l.RightPush(1, 2, 3)
+		_, _ = l2.RightPush(4, 5, 6)
+		values, source, err := l.MultiPop(list.Left, 2, l2)
+		assert.Nil(t, err)
+		assert.Equal(t, l, source)
+
+		ints, _ := redis.Ints(values, nil)
+		assert.Equal(t, []int{1, 2}, ints)
+	})
+
+	t.Run("first list empty", func(t *testing.T) {
+		_, _ = l.Base().Delete()
+		values, source, err := l.MultiPop(list.Right, 0, l2)
+		assert.Nil(t, err)
+		assert.Equal(t, l2, source)
+
+		ints, _ := redis.Ints(values, nil)
+		assert.Equal(t, []int{6}, ints)
+	})
+
+	t.Run("count greater than length", func(t *testing.T) {
+		values, _, err := l.MultiPop(list.Left, 10, l2)
+		assert.Nil(t, err)
+
+		ints, _ := redis.Ints(values, nil)
+		assert.Equal(t, []int{4, 5}, ints)
+	})
+}
+
+func TestRedisList_Pos(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := l.Pos(1, list.PosOptions{})
+		assert.Nil(t, err)
+		assert.EqualValues(t, -1, value)
+	})
+
+	_, _ = l.RightPush("a", "b", "c", "b", "d", "b")
+
+	scenarios := []struct {
+		name    string
+		value   string
+		options list.PosOptions
+		want    int64
+	}{
+		{name: "first", value: "b", want: 1},
+		{name: "not found", value: "e", want: -1},
+		{name: "rank", value: "b", options: list.PosOptions{Rank: 2}, want: 3},
+		{name: "negative rank", value: "b", options: list.PosOptions{Rank: -1}, want: 5},
+		{name: "max length", value: "d", options: list.PosOptions{MaxLen: 4}, want: -1},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			value, err := l.Pos(scenario.value, scenario.options)
+			assert.Nil(t, err)
+			assert.Equal(t, scenario.want, value)
+		})
+	}
+}
+
+func TestRedisList_Positions(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := l.Positions(1, 0, list.PosOptions{})
+		assert.Nil(t, err)
+		assert.Empty(t, value)
+	})
+
+	_, _ = l.RightPush("a", "b", "c", "b", "d", "b")
+
+	scenarios := []struct {
+		name    string
+		count   uint64
+		options list.PosOptions
+		want    []int64
+	}{
+		{name: "all", count: 0, want: []int64{1, 3, 5}},
+		{name: "count", count: 2, want: []int64{1, 3}},
+		{name: "rank", count: 0, options: list.PosOptions{Rank: 2}, want: []int64{3, 5}},
+		{name: "negative rank", count: 2, options: list.PosOptions{Rank: -1}, want: []int64{5, 3}},
+		{name: "max length", count: 0, options: list.PosOptions{MaxLen: 4}, want: []int64{1, 3}},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			value, err := l.Positions("b", scenario.count, scenario.options)
+			assert.Nil(t, err)
+			assert.Equal(t, scenario.want, value)
+		})
+	}
+}
+
 func TestRedisList_Range(t *testing.T) {
 	l := list.NewRedisList(conn, test.RandomKey())
 	defer l.Base().Delete()
@@ -684,6 +1018,145 @@ func TestRedisList_RightPushX(t *testing.T) {
 	})
 }
 
+func TestRedisList_WithContext(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := l.WithContext(ctx).RightPush(1)
+		assert.Equal(t, context.Canceled, err)
+
+		_, err = l.WithContext(ctx).Base().Exists()
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("cancel blocking pop", func(t *testing.T) {
+		_, _ = l.Base().Delete()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		_, err := l.WithContext(ctx).BlockingLeftPop(0)
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, time.Since(start) < 3*time.Second)
+
+		// The connection is still usable
+		length, err := l.Length()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, length)
+	})
+
+	t.Run("blocking pop with context", func(t *testing.T) {
+		_, _ = l.Base().Delete()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, _ = l.RightPush("abc")
+		value, err := redis.String(l.WithContext(ctx).BlockingRightPop(5 * time.Second))
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", value)
+	})
+}
+
+func TestBlockingPop(t *testing.T) {
+	high := list.NewRedisList(conn, test.RandomKey())
+	defer high.Base().Delete()
+
+	low := list.NewRedisList(conn, test.RandomKey())
+	defer low.Base().Delete()
+
+	t.Run("no lists", func(t *testing.T) {
+		_, _, err := list.BlockingPop(context.Background(), redistypes.SingleConn(conn), time.Second, list.Left)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, _, err := list.BlockingPop(context.Background(), redistypes.SingleConn(conn), 1500*time.Millisecond, list.Left, high, low)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("unknown direction", func(t *testing.T) {
+		_, _ = high.RightPush(1)
+		defer high.Base().Delete()
+
+		_, _, err := list.BlockingPop(context.Background(), redistypes.SingleConn(conn), time.Second, "UP", high, low)
+		assert.NotNil(t, err)
+
+		length, _ := high.Length()
+		assert.EqualValues(t, 1, length)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		value, source, err := list.BlockingPop(context.Background(), redistypes.SingleConn(conn), time.Second, list.Left, high, low)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+		assert.Nil(t, source)
+	})
+
+	t.Run("priority order", func(t *testing.T) {
+		_, _ = high.RightPush(1, 2)
+		_, _ = low.RightPush(3, 4)
+
+		value, source, err := list.BlockingPop(context.Background(), redistypes.SingleConn(conn), time.Second, list.Left, high, low)
+		assert.Nil(t, err)
+		test.AssertEqual(t, 1, value)
+		assert.Equal(t, high, source)
+
+		value, source, err = list.BlockingPop(context.Background(), redistypes.SingleConn(conn), time.Second, list.Right, high, low)
+		assert.Nil(t, err)
+		test.AssertEqual(t, 2, value)
+		assert.Equal(t, high, source)
+
+		value, source, err = list.BlockingPop(context.Background(), redistypes.SingleConn(conn), time.Second, list.Right, high, low)
+		assert.Nil(t, err)
+		test.AssertEqual(t, 4, value)
+		assert.Equal(t, low, source)
+	})
+
+	t.Run("blocking test", func(t *testing.T) {
+		_, _ = high.Base().Delete()
+		_, _ = low.Base().Delete()
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+
+			conn2 := redis.NewConn(netConn, 5*time.Second, 5*time.Second)
+			defer conn2.Close()
+
+			high2 := list.NewRedisList(conn2, high.Base().Name())
+			low2 := list.NewRedisList(conn2, low.Base().Name())
+
+			value, source, err := list.BlockingPop(context.Background(), redistypes.SingleConn(conn2), 2*time.Second, list.Left, high2, low2)
+
+			assert.Nil(t, err)
+			test.AssertEqual(t, 5, value)
+			assert.Equal(t, low2, source)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(200 * time.Millisecond)
+			_, err := low.RightPush(5)
+			assert.Nil(t, err)
+		}()
+
+		wg.Wait()
+	})
+}
+
 func TestMain(m *testing.M) {
 	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
 	if err != nil {
@@ -0,0 +1,200 @@
+package list
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// MultiPopFuture is a Future for the reply to LMPOP.
+type MultiPopFuture struct {
+	*redistypes.Future
+	lists []List
+}
+
+// Result returns the values that were popped and the List they came from, like
+// List.MultiPop.
+func (f MultiPopFuture) Result() ([]interface{}, List, error) {
+	reply, err := f.Reply()
+	return multiPopReply(reply, err, f.lists)
+}
+
+// PosFuture is a Future for the reply to LPOS without COUNT.
+type PosFuture struct {
+	*redistypes.Future
+}
+
+// Result returns the index of the value, or -1 if it wasn't found, like List.Pos.
+func (f PosFuture) Result() (int64, error) {
+	return posReply(f.Reply())
+}
+
+// PositionsFuture is a Future for the reply to LPOS with COUNT.
+type PositionsFuture struct {
+	*redistypes.Future
+}
+
+// Result returns the indices of the value, like List.Positions.
+func (f PositionsFuture) Result() ([]int64, error) {
+	return redis.Int64s(f.Reply())
+}
+
+// Pipelined is a List whose commands are queued in a redistypes.Batch instead of being
+// sent immediately. Each method returns a future that is resolved when the Batch is
+// executed. The methods work like the List methods with the same names. The blocking
+// commands are not available, since they would hold up the rest of the Batch.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Index queues the Redis command LINDEX. See List.Index.
+	Index(index int64) *redistypes.Future
+
+	// Insert queues the Redis command LINSERT. See List.Insert.
+	Insert(adj Adjacency, pivot interface{}, value interface{}) redistypes.Int64Future
+
+	// LeftPop queues the Redis command LPOP. See List.LeftPop.
+	LeftPop() *redistypes.Future
+
+	// LeftPush queues the Redis command LPUSH. See List.LeftPush.
+	LeftPush(args ...interface{}) redistypes.Uint64Future
+
+	// LeftPushX queues the Redis command LPUSHX. See List.LeftPushX.
+	LeftPushX(arg interface{}) redistypes.Uint64Future
+
+	// Length queues the Redis command LLEN. See List.Length.
+	Length() redistypes.Uint64Future
+
+	// Move queues the Redis command LMOVE. See List.Move.
+	Move(destination List, from, to Direction) *redistypes.Future
+
+	// MultiPop queues the Redis command LMPOP. See List.MultiPop.
+	MultiPop(dir Direction, count int64, others ...List) MultiPopFuture
+
+	// Pos queues the Redis command LPOS. See List.Pos.
+	Pos(value interface{}, options PosOptions) PosFuture
+
+	// Positions queues the Redis command LPOS with COUNT. See List.Positions.
+	Positions(value interface{}, count uint64, options PosOptions) PositionsFuture
+
+	// Range queues the Redis command LRANGE. See List.Range.
+	Range(start, stop int64) redistypes.ValuesFuture
+
+	// Remove queues the Redis command LREM. See List.Remove.
+	Remove(count int64, value interface{}) redistypes.Uint64Future
+
+	// RightPop queues the Redis command RPOP. See List.RightPop.
+	RightPop() *redistypes.Future
+
+	// RightPopLeftPush queues the Redis command RPOPLPUSH. See List.RightPopLeftPush.
+	//
+	// Deprecated: use Move with Right and Left instead.
+	RightPopLeftPush(destination List) *redistypes.Future
+
+	// RightPush queues the Redis command RPUSH. See List.RightPush.
+	RightPush(args ...interface{}) redistypes.Uint64Future
+
+	// RightPushX queues the Redis command RPUSHX. See List.RightPushX.
+	RightPushX(arg interface{}) redistypes.Uint64Future
+
+	// Set queues the Redis command LSET. See List.Set.
+	Set(index int64, value interface{}) redistypes.StatusFuture
+
+	// Trim queues the Redis command LTRIM. See List.Trim.
+	Trim(start, stop int64) redistypes.StatusFuture
+}
+
+type pipelinedList struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+	list  List
+}
+
+// NewPipelined creates a Pipelined list that queues the commands of l in b.
+func NewPipelined(b redistypes.Batch, l List) Pipelined {
+	return &pipelinedList{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, l.Base()),
+		list:  l,
+	}
+}
+
+func (r pipelinedList) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedList) Index(index int64) *redistypes.Future {
+	return r.batch.Queue("LINDEX", r.base.Name(), index)
+}
+
+func (r *pipelinedList) Insert(adj Adjacency, pivot interface{}, value interface{}) redistypes.Int64Future {
+	return redistypes.Int64Future{Future: r.batch.Queue("LINSERT", r.base.Name(), string(adj), pivot, value)}
+}
+
+func (r *pipelinedList) LeftPop() *redistypes.Future {
+	return r.batch.Queue("LPOP", r.base.Name())
+}
+
+func (r *pipelinedList) LeftPush(args ...interface{}) redistypes.Uint64Future {
+	args = internal.PrependInterface(r.base.Name(), args...)
+	return redistypes.Uint64Future{Future: r.batch.Queue("LPUSH", args...)}
+}
+
+func (r *pipelinedList) LeftPushX(arg interface{}) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("LPUSHX", r.base.Name(), arg)}
+}
+
+func (r *pipelinedList) Length() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("LLEN", r.base.Name())}
+}
+
+func (r *pipelinedList) Move(destination List, from, to Direction) *redistypes.Future {
+	return r.batch.Queue("LMOVE", r.base.Name(), destination.Base().Name(), string(from), string(to))
+}
+
+func (r *pipelinedList) MultiPop(dir Direction, count int64, others ...List) MultiPopFuture {
+	lists := append([]List{r.list}, others...)
+	return MultiPopFuture{Future: r.batch.Queue("LMPOP", multiPopArgs(dir, count, lists)...), lists: lists}
+}
+
+func (r *pipelinedList) Pos(value interface{}, options PosOptions) PosFuture {
+	args := append([]interface{}{r.base.Name(), value}, options.args()...)
+	return PosFuture{Future: r.batch.Queue("LPOS", args...)}
+}
+
+func (r *pipelinedList) Positions(value interface{}, count uint64, options PosOptions) PositionsFuture {
+	return PositionsFuture{Future: r.batch.Queue("LPOS", positionsArgs(r.base.Name(), value, count, options)...)}
+}
+
+func (r *pipelinedList) Range(start, stop int64) redistypes.ValuesFuture {
+	return redistypes.ValuesFuture{Future: r.batch.Queue("LRANGE", r.base.Name(), start, stop)}
+}
+
+func (r *pipelinedList) Remove(count int64, value interface{}) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("LREM", r.base.Name(), count, value)}
+}
+
+func (r *pipelinedList) RightPop() *redistypes.Future {
+	return r.batch.Queue("RPOP", r.base.Name())
+}
+
+func (r *pipelinedList) RightPopLeftPush(destination List) *redistypes.Future {
+	return r.batch.Queue("RPOPLPUSH", r.base.Name(), destination.Base().Name())
+}
+
+func (r *pipelinedList) RightPush(args ...interface{}) redistypes.Uint64Future {
+	args = internal.PrependInterface(r.base.Name(), args...)
+	return redistypes.Uint64Future{Future: r.batch.Queue("RPUSH", args...)}
+}
+
+func (r *pipelinedList) RightPushX(arg interface{}) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("RPUSHX", r.base.Name(), arg)}
+}
+
+func (r *pipelinedList) Set(index int64, value interface{}) redistypes.StatusFuture {
+	return redistypes.StatusFuture{Future: r.batch.Queue("LSET", r.base.Name(), index, value)}
+}
+
+func (r *pipelinedList) Trim(start, stop int64) redistypes.StatusFuture {
+	return redistypes.StatusFuture{Future: r.batch.Queue("LTRIM", r.base.Name(), start, stop)}
+}
@@ -0,0 +1,153 @@
+package list_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/list"
+	"github.com/MasterOfBinary/redistypes/set"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	l2 := list.NewRedisList(conn, test.RandomKey())
+	defer l2.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	pl := list.NewPipelined(p, l)
+	assert.Equal(t, l.Base().Name(), pl.Base().Name())
+
+	rightPush := pl.RightPush("b", "c", "d")
+	leftPush := pl.LeftPush("a")
+	insert := pl.Insert(list.After, "d", "e")
+	set := pl.Set(0, "z")
+	index := pl.Index(0)
+	move := pl.RightPopLeftPush(l2)
+	length := pl.Length()
+	values := pl.Range(0, -1)
+	exists := pl.Base().Exists()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	count, err := rightPush.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, count)
+
+	count, err = leftPush.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, count)
+
+	inserted, err := insert.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, inserted)
+
+	assert.Nil(t, set.Result())
+
+	value, err := redis.String(index.Reply())
+	assert.Nil(t, err)
+	assert.Equal(t, "z", value)
+
+	value, err = redis.String(move.Reply())
+	assert.Nil(t, err)
+	assert.Equal(t, "e", value)
+
+	count, err = length.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, count)
+
+	strs, err := redis.Strings(values.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"z", "b", "c", "d"}, strs)
+
+	ok, err := exists.Result()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestPipelined_transaction(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = l.RightPush("abc", "def")
+
+	tx := redistypes.NewTransaction(redistypes.SingleConn(conn))
+	pop := list.NewPipelined(tx, l).LeftPop()
+	add := set.NewPipelined(tx, s).Add("abc")
+
+	err := tx.Exec(context.Background())
+	assert.Nil(t, err)
+
+	value, err := redis.String(pop.Reply())
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", value)
+
+	count, err := add.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestPipelined_modern(t *testing.T) {
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	l2 := list.NewRedisList(conn, test.RandomKey())
+	defer l2.Base().Delete()
+
+	_, _ = l.RightPush("a", "b", "a", "c", "d")
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	pl := list.NewPipelined(p, l)
+	pos := pl.Pos("a", list.PosOptions{Rank: 2})
+	missing := pl.Pos("z", list.PosOptions{})
+	positions := pl.Positions("a", 0, list.PosOptions{})
+	move := pl.Move(l2, list.Right, list.Left)
+	pop := pl.MultiPop(list.Left, 2, l2)
+	popOther := list.NewPipelined(p, l2).MultiPop(list.Left, 1)
+	popEmpty := list.NewPipelined(p, l2).MultiPop(list.Left, 1)
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	index, err := pos.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, index)
+
+	index, err = missing.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, -1, index)
+
+	indices, err := positions.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 2}, indices)
+
+	value, err := redis.String(move.Reply())
+	assert.Nil(t, err)
+	assert.Equal(t, "d", value)
+
+	values, from, err := pop.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, l, from)
+	strs, err := redis.Strings(values, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, strs)
+
+	values, from, err = popOther.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{[]byte("d")}, values)
+	assert.Equal(t, l2, from)
+
+	values, from, err = popEmpty.Result()
+	assert.Nil(t, err)
+	assert.Nil(t, values)
+	assert.Nil(t, from)
+}
@@ -0,0 +1,352 @@
+package redistypes
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrNotExecuted is returned by a Future whose command has not been executed yet.
+var ErrNotExecuted = errors.New("Command has not been executed")
+
+// Batch queues commands so they can be sent to Redis together. The commands of a
+// Type can be queued in a Batch with NewPipelinedType, and each data type package
+// has a similar function for its own commands.
+type Batch interface {
+	// Queue queues the command cmd with args and returns a Future for its reply.
+	Queue(cmd string, args ...interface{}) *Future
+}
+
+// Future is the reply to a command queued in a Batch. It is resolved when the Batch
+// is executed.
+type Future struct {
+	reply    interface{}
+	err      error
+	resolved bool
+}
+
+// Reply returns the reply to the command, or the error returned by Redis for it. If
+// the command has not been executed, ErrNotExecuted is returned.
+func (f *Future) Reply() (interface{}, error) {
+	if !f.resolved {
+		return nil, ErrNotExecuted
+	}
+	return f.reply, f.err
+}
+
+func (f *Future) resolve(reply interface{}, err error) {
+	f.reply = reply
+	f.err = err
+	f.resolved = true
+}
+
+// failedFuture returns a Future that is already resolved to err. It is used for
+// commands with invalid arguments, which are never queued.
+func failedFuture(err error) *Future {
+	f := &Future{}
+	f.resolve(nil, err)
+	return f
+}
+
+// BoolFuture is a Future for a reply that is converted using redis.Bool.
+type BoolFuture struct {
+	*Future
+}
+
+// Result returns the reply to the command as a bool.
+func (f BoolFuture) Result() (bool, error) {
+	return redis.Bool(f.Reply())
+}
+
+// Int64Future is a Future for a reply that is converted using redis.Int64.
+type Int64Future struct {
+	*Future
+}
+
+// Result returns the reply to the command as an int64.
+func (f Int64Future) Result() (int64, error) {
+	return redis.Int64(f.Reply())
+}
+
+// Float64Future is a Future for a reply that is converted using redis.Float64.
+type Float64Future struct {
+	*Future
+}
+
+// Result returns the reply to the command as a float64.
+func (f Float64Future) Result() (float64, error) {
+	return redis.Float64(f.Reply())
+}
+
+// Uint64Future is a Future for a reply that is converted using redis.Uint64.
+type Uint64Future struct {
+	*Future
+}
+
+// Result returns the reply to the command as a uint64.
+func (f Uint64Future) Result() (uint64, error) {
+	return redis.Uint64(f.Reply())
+}
+
+// ValuesFuture is a Future for a reply that is converted using redis.Values.
+type ValuesFuture struct {
+	*Future
+}
+
+// Result returns the reply to the command as a slice of values.
+func (f ValuesFuture) Result() ([]interface{}, error) {
+	return redis.Values(f.Reply())
+}
+
+// StatusFuture is a Future for a command whose reply is only a status.
+type StatusFuture struct {
+	*Future
+}
+
+// Result returns the error returned for the command, if any.
+func (f StatusFuture) Result() error {
+	_, err := f.Reply()
+	return err
+}
+
+// DurationFuture is a Future for the reply to TTL or PTTL.
+type DurationFuture struct {
+	*Future
+	unit time.Duration
+}
+
+// Result returns the reply to the command as a duration. As with Type.TTL, it returns
+// ErrKeyNotFound or ErrNoExpiry if the key doesn't exist or doesn't expire.
+func (f DurationFuture) Result() (time.Duration, error) {
+	reply, err := f.Reply()
+	return expiryDuration(reply, err, f.unit)
+}
+
+// commandQueue holds the commands queued in a Batch.
+type commandQueue struct {
+	commands []queuedCommand
+}
+
+type queuedCommand struct {
+	name   string
+	args   []interface{}
+	future *Future
+}
+
+func (q *commandQueue) queue(cmd string, args []interface{}) *Future {
+	f := &Future{}
+	q.commands = append(q.commands, queuedCommand{
+		name:   cmd,
+		args:   args,
+		future: f,
+	})
+	return f
+}
+
+// take empties the queue and returns the commands that were in it.
+func (q *commandQueue) take() []queuedCommand {
+	commands := q.commands
+	q.commands = nil
+	return commands
+}
+
+// failUnresolved resolves the futures of commands that haven't been resolved to err.
+func failUnresolved(commands []queuedCommand, err error) {
+	for _, command := range commands {
+		if !command.future.resolved {
+			command.future.resolve(nil, err)
+		}
+	}
+}
+
+// Pipeline is a Batch that sends all of its commands to Redis in a single round
+// trip when it is executed. The commands are not atomic; to run commands atomically,
+// see Transaction.
+type Pipeline struct {
+	provider ConnProvider
+	queue    commandQueue
+}
+
+// NewPipeline creates a Pipeline that borrows a connection from p each time it is
+// executed.
+func NewPipeline(p ConnProvider) *Pipeline {
+	return &Pipeline{
+		provider: p,
+	}
+}
+
+// Queue queues the command cmd with args and returns a Future for its reply.
+func (p *Pipeline) Queue(cmd string, args ...interface{}) *Future {
+	return p.queue.queue(cmd, args)
+}
+
+// Len returns the number of commands queued in the Pipeline.
+func (p *Pipeline) Len() int {
+	return len(p.queue.commands)
+}
+
+// Exec sends the queued commands to Redis and resolves their futures. An error returned
+// by Redis for a single command is reported by its Future and doesn't stop the other
+// commands. Exec only returns an error if the commands could not be sent or their
+// replies could not be read, in which case the futures that haven't been resolved
+// are resolved to that error.
+//
+// After Exec, the Pipeline is empty and can be used again.
+func (p *Pipeline) Exec(ctx context.Context) error {
+	commands := p.queue.take()
+
+	err := p.exec(ctx, commands)
+	if err != nil {
+		failUnresolved(commands, err)
+	}
+	return err
+}
+
+func (p *Pipeline) exec(ctx context.Context, commands []queuedCommand) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	conn, err := internal.GetConn(ctx, p.provider)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, command := range commands {
+		if err = conn.Send(command.name, command.args...); err != nil {
+			return err
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		reply, err := internal.ReceiveContext(ctx, conn)
+		if _, ok := err.(redis.Error); err != nil && !ok {
+			return err
+		}
+		command.future.resolve(reply, err)
+	}
+
+	return nil
+}
+
+// PipelinedType is a Type whose commands are queued in a Batch instead of being
+// sent immediately. Each method returns a future that is resolved when the Batch
+// is executed. The methods work like the Type methods with the same names.
+type PipelinedType interface {
+	// Name returns the name of the key in Redis.
+	Name() string
+
+	// Copy queues the Redis command COPY. See Type.Copy.
+	Copy(destination string, replace bool) BoolFuture
+
+	// Delete queues the Redis command DEL. See Type.Delete.
+	Delete() BoolFuture
+
+	// Exists queues the Redis command EXISTS. See Type.Exists.
+	Exists() BoolFuture
+
+	// Expire queues the Redis command EXPIRE. See Type.Expire.
+	Expire(timeout time.Duration, conditions ...ExpireCondition) BoolFuture
+
+	// ExpireAt queues the Redis command EXPIREAT. See Type.ExpireAt.
+	ExpireAt(t time.Time, conditions ...ExpireCondition) BoolFuture
+
+	// PExpire queues the Redis command PEXPIRE. See Type.PExpire.
+	PExpire(timeout time.Duration, conditions ...ExpireCondition) BoolFuture
+
+	// PExpireAt queues the Redis command PEXPIREAT. See Type.PExpireAt.
+	PExpireAt(t time.Time, conditions ...ExpireCondition) BoolFuture
+
+	// PTTL queues the Redis command PTTL. See Type.PTTL.
+	PTTL() DurationFuture
+
+	// Persist queues the Redis command PERSIST. See Type.Persist.
+	Persist() BoolFuture
+
+	// TTL queues the Redis command TTL. See Type.TTL.
+	TTL() DurationFuture
+}
+
+type pipelinedType struct {
+	batch Batch
+	base  Type
+}
+
+// NewPipelinedType creates a PipelinedType that queues the commands of t in b.
+func NewPipelinedType(b Batch, t Type) PipelinedType {
+	return &pipelinedType{
+		batch: b,
+		base:  t,
+	}
+}
+
+func (r pipelinedType) Name() string {
+	return r.base.Name()
+}
+
+func (r *pipelinedType) Copy(destination string, replace bool) BoolFuture {
+	args := []interface{}{r.Name(), destination}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	return BoolFuture{r.batch.Queue("COPY", args...)}
+}
+
+func (r *pipelinedType) Delete() BoolFuture {
+	return BoolFuture{r.batch.Queue("DEL", r.Name())}
+}
+
+func (r *pipelinedType) Exists() BoolFuture {
+	return BoolFuture{r.batch.Queue("EXISTS", r.Name())}
+}
+
+func (r *pipelinedType) Expire(timeout time.Duration, conditions ...ExpireCondition) BoolFuture {
+	seconds, err := durationSeconds(timeout)
+	if err != nil {
+		return BoolFuture{failedFuture(err)}
+	}
+	return BoolFuture{r.batch.Queue("EXPIRE", expireArgs(r.Name(), seconds, conditions)...)}
+}
+
+func (r *pipelinedType) ExpireAt(t time.Time, conditions ...ExpireCondition) BoolFuture {
+	seconds, err := timeSeconds(t)
+	if err != nil {
+		return BoolFuture{failedFuture(err)}
+	}
+	return BoolFuture{r.batch.Queue("EXPIREAT", expireArgs(r.Name(), seconds, conditions)...)}
+}
+
+func (r *pipelinedType) PExpire(timeout time.Duration, conditions ...ExpireCondition) BoolFuture {
+	ms, err := durationMilliseconds(timeout)
+	if err != nil {
+		return BoolFuture{failedFuture(err)}
+	}
+	return BoolFuture{r.batch.Queue("PEXPIRE", expireArgs(r.Name(), ms, conditions)...)}
+}
+
+func (r *pipelinedType) PExpireAt(t time.Time, conditions ...ExpireCondition) BoolFuture {
+	ms, err := timeMilliseconds(t)
+	if err != nil {
+		return BoolFuture{failedFuture(err)}
+	}
+	return BoolFuture{r.batch.Queue("PEXPIREAT", expireArgs(r.Name(), ms, conditions)...)}
+}
+
+func (r *pipelinedType) PTTL() DurationFuture {
+	return DurationFuture{r.batch.Queue("PTTL", r.Name()), time.Millisecond}
+}
+
+func (r *pipelinedType) Persist() BoolFuture {
+	return BoolFuture{r.batch.Queue("PERSIST", r.Name())}
+}
+
+func (r *pipelinedType) TTL() DurationFuture {
+	return DurationFuture{r.batch.Queue("TTL", r.Name()), time.Second}
+}
@@ -0,0 +1,135 @@
+package redistypes_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline_Exec(t *testing.T) {
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+
+	t.Run("empty pipeline", func(t *testing.T) {
+		err := p.Exec(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("several commands", func(t *testing.T) {
+		name := test.RandomKey()
+		defer test.DeleteKey(name, conn)
+
+		set := p.Queue("SET", name, 1)
+		incr := p.Queue("INCR", name)
+		get := p.Queue("GET", name)
+		assert.Equal(t, 3, p.Len())
+
+		_, err := get.Reply()
+		assert.Equal(t, redistypes.ErrNotExecuted, err)
+
+		err = p.Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, p.Len())
+
+		status, err := redis.String(set.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", status)
+
+		value, err := redis.Int(incr.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, value)
+
+		value, err = redis.Int(get.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("error in one command", func(t *testing.T) {
+		name := test.RandomKey()
+		defer test.DeleteKey(name, conn)
+
+		push := p.Queue("RPUSH", name, 1)
+		incr := p.Queue("INCR", name)
+		length := p.Queue("LLEN", name)
+
+		err := p.Exec(context.Background())
+		assert.Nil(t, err)
+
+		_, err = push.Reply()
+		assert.Nil(t, err)
+
+		_, err = incr.Reply()
+		assert.NotNil(t, err)
+
+		value, err := redis.Int(length.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		f := p.Queue("PING")
+		err := p.Exec(ctx)
+		assert.Equal(t, context.Canceled, err)
+
+		_, err = f.Reply()
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestPipelinedType(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	pr := redistypes.NewPipelinedType(p, r)
+	assert.Equal(t, r.Name(), pr.Name())
+
+	_, _ = conn.Do("SET", r.Name(), 1)
+
+	exists := pr.Exists()
+	expire := pr.Expire(10 * time.Second)
+	invalid := pr.PExpire(5 * time.Nanosecond)
+	ttl := pr.TTL()
+	persist := pr.Persist()
+	pttl := pr.PTTL()
+	deleted := pr.Delete()
+
+	// invalid was never queued
+	assert.Equal(t, 6, p.Len())
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	value, err := exists.Result()
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = expire.Result()
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	_, err = invalid.Result()
+	assert.NotNil(t, err)
+
+	duration, err := ttl.Result()
+	assert.Nil(t, err)
+	assert.True(t, duration > 8*time.Second && duration <= 10*time.Second)
+
+	value, err = persist.Result()
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	_, err = pttl.Result()
+	assert.Equal(t, redistypes.ErrNoExpiry, err)
+
+	value, err = deleted.Result()
+	assert.Nil(t, err)
+	assert.True(t, value)
+}
@@ -0,0 +1,68 @@
+package redistypes
+
+import (
+	"sync"
+
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ConnProvider provides connections to Redis. Every command borrows a connection
+// from the provider with Get, and closes it when the reply has been read. *redis.Pool
+// implements ConnProvider; since it hands out a healthy connection for each command,
+// types created with a pool are safe to use from multiple goroutines.
+type ConnProvider interface {
+	// Get returns a connection. If no connection can be made, the returned
+	// connection's Err method returns the reason.
+	Get() redis.Conn
+}
+
+// SingleConn returns a ConnProvider that always provides conn. Closing a connection
+// returned by it does not close conn. Like conn itself, types created with it are
+// not safe for concurrent use.
+func SingleConn(conn redis.Conn) ConnProvider {
+	return singleConn{conn}
+}
+
+type singleConn struct {
+	conn redis.Conn
+}
+
+func (p singleConn) Get() redis.Conn {
+	return internal.NopCloseConn(p.conn)
+}
+
+// Reconnecting returns a ConnProvider that provides a single connection created by
+// dial. If the connection breaks, it is closed and a new one is created by calling
+// dial the next time a connection is needed. Get is safe to call from multiple
+// goroutines, but the connection it provides is shared, so types created with it
+// are not safe for concurrent use. Use a *redis.Pool for that.
+func Reconnecting(dial func() (redis.Conn, error)) ConnProvider {
+	return &reconnecting{dial: dial}
+}
+
+type reconnecting struct {
+	mu   sync.Mutex
+	dial func() (redis.Conn, error)
+	conn redis.Conn
+}
+
+func (p *reconnecting) Get() redis.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn != nil && p.conn.Err() != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+
+	if p.conn == nil {
+		conn, err := p.dial()
+		if err != nil {
+			return internal.ErrorConn(err)
+		}
+		p.conn = conn
+	}
+
+	return internal.NopCloseConn(p.conn)
+}
@@ -0,0 +1,94 @@
+package redistypes_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleConn(t *testing.T) {
+	p := redistypes.SingleConn(conn)
+
+	c := p.Get()
+	assert.Nil(t, c.Close())
+
+	// conn is still open
+	_, err := conn.Do("PING")
+	assert.Nil(t, err)
+}
+
+func TestReconnecting(t *testing.T) {
+	dials := 0
+	p := redistypes.Reconnecting(func() (redis.Conn, error) {
+		dials++
+		return redis.Dial("tcp", internal.GetHostAndPort())
+	})
+
+	r := redistypes.NewRedisTypeFromProvider(p, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("reuses connection", func(t *testing.T) {
+		_, err := r.Exists()
+		assert.Nil(t, err)
+		_, err = r.Exists()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, dials)
+	})
+
+	t.Run("reconnects broken connection", func(t *testing.T) {
+		// Break the connection by timing out while waiting for a reply
+		c := p.Get()
+		_, _ = redis.DoWithTimeout(c, 10*time.Millisecond, "BLPOP", test.RandomKey(), 1)
+		assert.NotNil(t, c.Err())
+
+		_, err := r.Exists()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, dials)
+	})
+
+	t.Run("dial error", func(t *testing.T) {
+		wantErr := errors.New("unable to dial")
+		p := redistypes.Reconnecting(func() (redis.Conn, error) {
+			return nil, wantErr
+		})
+
+		_, err := redistypes.NewRedisTypeFromProvider(p, test.RandomKey()).Exists()
+		assert.Equal(t, wantErr, err)
+	})
+}
+
+func TestNewRedisTypeFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	r := redistypes.NewRedisTypeFromProvider(pool, test.RandomKey())
+	defer r.Delete()
+
+	_, _ = conn.Do("SET", r.Name(), 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exists, err := r.Exists()
+			assert.Nil(t, err)
+			assert.True(t, exists)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, pool.ActiveCount()-pool.IdleCount())
+}
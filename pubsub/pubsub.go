@@ -0,0 +1,41 @@
+// Package pubsub contains a Go implementation of the publish/subscribe messaging in Redis, where
+// messages published to a channel are delivered to every client subscribed to it. For more
+// information about how it works, see the Redis documentation.
+package pubsub
+
+import (
+	"context"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// Message is a message received by a Subscriber.
+type Message struct {
+	// Channel is the channel the message was published to.
+	Channel string
+
+	// Pattern is the pattern that matched Channel, if the message was received through
+	// a subscription made with PSubscribe. Otherwise it is empty.
+	Pattern string
+
+	// Data is the content of the message.
+	Data []byte
+}
+
+// Publish implements the Redis command PUBLISH. It borrows a connection from p, publishes
+// message to channel and returns the number of clients that received it.
+//
+// See https://redis.io/commands/publish.
+func Publish(ctx context.Context, p redistypes.ConnProvider, channel string, message interface{}) (uint64, error) {
+	return redis.Uint64(internal.Do(ctx, p, "PUBLISH", channel, message))
+}
+
+// SPublish implements the Redis command SPUBLISH. It works like Publish, but it publishes
+// to a shard channel, which is only delivered to clients subscribed with SSubscribe.
+//
+// See https://redis.io/commands/spublish.
+func SPublish(ctx context.Context, p redistypes.ConnProvider, channel string, message interface{}) (uint64, error) {
+	return redis.Uint64(internal.Do(ctx, p, "SPUBLISH", channel, message))
+}
@@ -0,0 +1,50 @@
+package pubsub_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/pubsub"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func TestPublish(t *testing.T) {
+	value, err := pubsub.Publish(context.Background(), redistypes.SingleConn(conn), test.RandomKey(), "hello")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = pubsub.Publish(ctx, redistypes.SingleConn(conn), test.RandomKey(), "hello")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSPublish(t *testing.T) {
+	value, err := pubsub.SPublish(context.Background(), redistypes.SingleConn(conn), test.RandomKey(), "hello")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,374 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrClosed is returned when the subscriptions of a Subscriber are changed after it
+// stopped.
+var ErrClosed = errors.New("Subscriber is closed")
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultReconnectDelay      = time.Second
+)
+
+// The commands that subscribe to each kind of channel, in the order they are sent when
+// a connection is opened.
+var subscribeCommands = []string{"SUBSCRIBE", "PSUBSCRIBE", "SSUBSCRIBE"}
+
+// SubscriberOptions are the options for NewSubscriber. Fields left at their zero value
+// use the defaults.
+type SubscriberOptions struct {
+	// HealthCheckInterval is how often the Subscriber pings Redis. If nothing is
+	// received for twice as long, the connection is considered broken and is replaced.
+	// The default is 30 seconds.
+	HealthCheckInterval time.Duration
+
+	// ReconnectDelay is how long the Subscriber waits before opening a new connection
+	// after one fails. The default is one second.
+	ReconnectDelay time.Duration
+
+	// BufferSize is the capacity of the Messages channel. The default is 0, so a
+	// message is only read from the connection once the previous one was received.
+	BufferSize int
+}
+
+// withDefaults returns the options with the defaults filled in.
+func (o SubscriberOptions) withDefaults() SubscriberOptions {
+	if o.HealthCheckInterval <= 0 {
+		o.HealthCheckInterval = defaultHealthCheckInterval
+	}
+	if o.ReconnectDelay <= 0 {
+		o.ReconnectDelay = defaultReconnectDelay
+	}
+	return o
+}
+
+// Subscriber receives the messages of the channels it is subscribed to on a dedicated
+// connection, and delivers them on the Messages channel.
+//
+// If the connection breaks, the Subscriber opens a new one and subscribes to the same
+// channels again. Messages published while it is reconnecting are lost, as Redis
+// doesn't keep them. The Subscriber stops when its context is done or Close is called,
+// which closes the connection and the Messages channel. It also stops if Redis replies
+// with an error, such as when SSUBSCRIBE isn't supported, since subscribing again
+// would fail the same way. Err returns that error.
+//
+// The methods of a Subscriber are safe for concurrent use.
+type Subscriber struct {
+	dial     func() (redis.Conn, error)
+	options  SubscriberOptions
+	messages chan Message
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// mu guards the fields below and the writes to conn.
+	mu     sync.Mutex
+	conn   redis.PubSubConn
+	closed bool
+	err    error
+	subs   map[string]map[string]struct{}
+}
+
+// NewSubscriber creates a Subscriber that opens its connections with dial, such as the Dial
+// function of a redis.Pool, and starts it. It has no subscriptions until one of the
+// subscribe methods is called. The Subscriber stops when ctx is done.
+func NewSubscriber(ctx context.Context, dial func() (redis.Conn, error), options SubscriberOptions) *Subscriber {
+	options = options.withDefaults()
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &Subscriber{
+		dial:     dial,
+		options:  options,
+		messages: make(chan Message, options.BufferSize),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		subs:     make(map[string]map[string]struct{}, len(subscribeCommands)),
+	}
+	for _, cmd := range subscribeCommands {
+		s.subs[cmd] = make(map[string]struct{})
+	}
+
+	go s.run(ctx)
+	return s
+}
+
+// Messages returns the channel on which the messages are delivered. It is closed when the
+// Subscriber stops.
+func (s *Subscriber) Messages() <-chan Message {
+	return s.messages
+}
+
+// Close stops the Subscriber and waits until its connection and the Messages channel are
+// closed. It returns the same error as Err.
+func (s *Subscriber) Close() error {
+	s.cancel()
+	<-s.done
+	return s.Err()
+}
+
+// Err returns the error reply from Redis that stopped the Subscriber. It returns nil while
+// the Subscriber is running, and if it was stopped by its context or Close. Broken
+// connections don't stop the Subscriber, so their errors are not returned.
+func (s *Subscriber) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Subscribe implements the Redis command SUBSCRIBE. It subscribes to channels. If the
+// Subscriber is not connected, they are subscribed to once it is. If the command can't
+// be sent because the connection broke, the error is returned, but the channels are
+// still subscribed to on the next connection. An error reply from Redis stops the
+// Subscriber and is returned by Err.
+//
+// See https://redis.io/commands/subscribe.
+func (s *Subscriber) Subscribe(channels ...string) error {
+	return s.update("SUBSCRIBE", "SUBSCRIBE", true, channels)
+}
+
+// PSubscribe implements the Redis command PSUBSCRIBE. It subscribes to the channels that
+// match patterns, such as "news.*", in the same way as Subscribe.
+//
+// See https://redis.io/commands/psubscribe.
+func (s *Subscriber) PSubscribe(patterns ...string) error {
+	return s.update("PSUBSCRIBE", "PSUBSCRIBE", true, patterns)
+}
+
+// SSubscribe implements the Redis command SSUBSCRIBE. It subscribes to shard channels, in
+// the same way as Subscribe.
+//
+// See https://redis.io/commands/ssubscribe.
+func (s *Subscriber) SSubscribe(channels ...string) error {
+	return s.update("SSUBSCRIBE", "SSUBSCRIBE", true, channels)
+}
+
+// Unsubscribe implements the Redis command UNSUBSCRIBE. It unsubscribes from channels, or
+// from all of the channels subscribed to with Subscribe if none are given.
+//
+// See https://redis.io/commands/unsubscribe.
+func (s *Subscriber) Unsubscribe(channels ...string) error {
+	return s.update("SUBSCRIBE", "UNSUBSCRIBE", false, channels)
+}
+
+// PUnsubscribe implements the Redis command PUNSUBSCRIBE. It unsubscribes from patterns,
+// or from all of the patterns if none are given.
+//
+// See https://redis.io/commands/punsubscribe.
+func (s *Subscriber) PUnsubscribe(patterns ...string) error {
+	return s.update("PSUBSCRIBE", "PUNSUBSCRIBE", false, patterns)
+}
+
+// SUnsubscribe implements the Redis command SUNSUBSCRIBE. It unsubscribes from shard
+// channels, or from all of them if none are given.
+//
+// See https://redis.io/commands/sunsubscribe.
+func (s *Subscriber) SUnsubscribe(channels ...string) error {
+	return s.update("SSUBSCRIBE", "SUNSUBSCRIBE", false, channels)
+}
+
+// update adds names to or removes them from the subscriptions made with the command
+// kind, and sends cmd with names if the Subscriber is connected. The subscriptions are
+// updated even if cmd can't be sent, so they are sent again on the next connection.
+func (s *Subscriber) update(kind, cmd string, add bool, names []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	} else if add && len(names) == 0 {
+		return nil
+	}
+
+	subs := s.subs[kind]
+	if !add && len(names) == 0 {
+		for name := range subs {
+			delete(subs, name)
+		}
+	}
+	for _, name := range names {
+		if add {
+			subs[name] = struct{}{}
+		} else {
+			delete(subs, name)
+		}
+	}
+
+	if s.conn.Conn == nil {
+		return nil
+	}
+	return send(s.conn, cmd, names)
+}
+
+// run connects and receives messages until ctx is done or Redis replies with an error.
+func (s *Subscriber) run(ctx context.Context) {
+	var replyErr error
+	defer close(s.done)
+	defer close(s.messages)
+	defer func() {
+		s.mu.Lock()
+		s.closed = true
+		s.err = replyErr
+		s.mu.Unlock()
+	}()
+
+	for ctx.Err() == nil {
+		if conn, err := s.connect(); err == nil {
+			err = s.listen(ctx, conn)
+			s.disconnect()
+			if _, ok := err.(redis.Error); ok {
+				replyErr = err
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.options.ReconnectDelay):
+		}
+	}
+}
+
+// connect opens a connection and subscribes to all of the channels on it.
+func (s *Subscriber) connect() (redis.PubSubConn, error) {
+	c, err := s.dial()
+	if err != nil {
+		return redis.PubSubConn{}, err
+	}
+	conn := redis.PubSubConn{Conn: c}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, cmd := range subscribeCommands {
+		if len(s.subs[cmd]) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(s.subs[cmd]))
+		for name := range s.subs[cmd] {
+			names = append(names, name)
+		}
+		if err = send(conn, cmd, names); err != nil {
+			conn.Close()
+			return redis.PubSubConn{}, err
+		}
+	}
+
+	s.conn = conn
+	return conn, nil
+}
+
+// disconnect closes the current connection.
+func (s *Subscriber) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn.Close()
+	s.conn = redis.PubSubConn{}
+}
+
+// listen delivers the messages received on conn until receiving fails or ctx is done,
+// and returns the error. An error reply from Redis is returned as a redis.Error.
+func (s *Subscriber) listen(ctx context.Context, conn redis.PubSubConn) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.healthCheck(ctx, conn, stop)
+
+	for {
+		m, err := receive(conn.Conn, 2*s.options.HealthCheckInterval)
+		if err != nil {
+			return err
+		} else if m == nil {
+			continue
+		}
+
+		select {
+		case s.messages <- *m:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// healthCheck pings Redis on conn regularly until stop is closed. If ctx is done first,
+// it closes conn so listen stops waiting for a reply.
+func (s *Subscriber) healthCheck(ctx context.Context, conn redis.PubSubConn, stop <-chan struct{}) {
+	ticker := time.NewTicker(s.options.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			err := conn.Ping("")
+			s.mu.Unlock()
+			if err != nil {
+				return
+			}
+		case <-ctx.Done():
+			conn.Close()
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// send sends cmd with names on conn.
+func send(conn redis.PubSubConn, cmd string, names []string) error {
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+
+	if err := conn.Conn.Send(cmd, args...); err != nil {
+		return err
+	}
+	return conn.Conn.Flush()
+}
+
+// receive reads a notification from conn, waiting at most timeout. It returns a Message
+// for the notifications that carry one, or nil for the others. It works like the Receive
+// method of redis.PubSubConn, which doesn't know about shard channels.
+func receive(conn redis.Conn, timeout time.Duration) (*Message, error) {
+	reply, err := redis.ReceiveWithTimeout(conn, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Outside of subscribed mode, PING has a plain reply instead of a notification.
+	values, ok := reply.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var kind string
+	if values, err = redis.Scan(values, &kind); err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case "message", "smessage":
+		var m Message
+		if _, err := redis.Scan(values, &m.Channel, &m.Data); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	case "pmessage":
+		var m Message
+		if _, err := redis.Scan(values, &m.Pattern, &m.Channel, &m.Data); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	case "subscribe", "psubscribe", "ssubscribe", "unsubscribe", "punsubscribe", "sunsubscribe", "pong":
+		return nil, nil
+	}
+	return nil, errors.New("Unknown pub/sub notification")
+}
@@ -0,0 +1,245 @@
+package pubsub_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/pubsub"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleNewSubscriber() {
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "localhost:6379")
+		},
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	s := pubsub.NewSubscriber(ctx, pool.Dial, pubsub.SubscriberOptions{})
+	_ = s.Subscribe("my_news")
+	_ = s.PSubscribe("my_alerts.*")
+
+	// Messages is closed when ctx is done.
+	for m := range s.Messages() {
+		fmt.Println(m.Channel, string(m.Data))
+	}
+}
+
+// dial opens a connection to the test server.
+func dial() (redis.Conn, error) {
+	return redis.Dial("tcp", internal.GetHostAndPort())
+}
+
+// receiveWhilePublishing publishes until a message is received from s, since a
+// subscription may not have been made yet, and returns the message.
+func receiveWhilePublishing(t *testing.T, s *pubsub.Subscriber, publish func() error) (pubsub.Message, bool) {
+	timeout := time.After(10 * time.Second)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case m, ok := <-s.Messages():
+			return m, ok
+		case <-ticker.C:
+			assert.Nil(t, publish())
+		case <-timeout:
+			t.Error("No message received")
+			return pubsub.Message{}, false
+		}
+	}
+}
+
+func TestSubscriber_Subscribe(t *testing.T) {
+	s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{})
+	defer s.Close()
+
+	channel := test.RandomKey()
+	assert.Nil(t, s.Subscribe(channel))
+
+	m, ok := receiveWhilePublishing(t, s, func() error {
+		_, err := pubsub.Publish(context.Background(), redistypes.SingleConn(conn), channel, "hello")
+		return err
+	})
+	assert.True(t, ok)
+	assert.Equal(t, pubsub.Message{Channel: channel, Data: []byte("hello")}, m)
+}
+
+func TestSubscriber_PSubscribe(t *testing.T) {
+	s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{})
+	defer s.Close()
+
+	prefix := test.RandomKey()
+	assert.Nil(t, s.PSubscribe(prefix+".*"))
+
+	m, ok := receiveWhilePublishing(t, s, func() error {
+		_, err := pubsub.Publish(context.Background(), redistypes.SingleConn(conn), prefix+".a", "hello")
+		return err
+	})
+	assert.True(t, ok)
+	assert.Equal(t, pubsub.Message{Channel: prefix + ".a", Pattern: prefix + ".*", Data: []byte("hello")}, m)
+}
+
+func TestSubscriber_SSubscribe(t *testing.T) {
+	s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{})
+	defer s.Close()
+
+	channel := test.RandomKey()
+	assert.Nil(t, s.SSubscribe(channel))
+
+	m, ok := receiveWhilePublishing(t, s, func() error {
+		_, err := pubsub.SPublish(context.Background(), redistypes.SingleConn(conn), channel, "hello")
+		return err
+	})
+	assert.True(t, ok)
+	assert.Equal(t, pubsub.Message{Channel: channel, Data: []byte("hello")}, m)
+}
+
+func TestSubscriber_Unsubscribe(t *testing.T) {
+	s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{})
+	defer s.Close()
+
+	channel, other := test.RandomKey(), test.RandomKey()
+	assert.Nil(t, s.Subscribe(channel, other))
+	assert.Nil(t, s.Unsubscribe(channel))
+
+	m, ok := receiveWhilePublishing(t, s, func() error {
+		_, err := pubsub.Publish(context.Background(), redistypes.SingleConn(conn), channel, "ignored")
+		if err != nil {
+			return err
+		}
+		_, err = pubsub.Publish(context.Background(), redistypes.SingleConn(conn), other, "hello")
+		return err
+	})
+	assert.True(t, ok)
+	assert.Equal(t, other, m.Channel)
+}
+
+func TestSubscriber_Reconnect(t *testing.T) {
+	s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{ReconnectDelay: 10 * time.Millisecond})
+	defer s.Close()
+
+	before, after := test.RandomKey(), test.RandomKey()
+	assert.Nil(t, s.Subscribe(before, after))
+
+	publish := func(channel string) func() error {
+		return func() error {
+			_, err := pubsub.Publish(context.Background(), redistypes.SingleConn(conn), channel, "hello")
+			return err
+		}
+	}
+	_, ok := receiveWhilePublishing(t, s, publish(before))
+	assert.True(t, ok)
+
+	_, err := conn.Do("CLIENT", "KILL", "TYPE", "pubsub")
+	assert.Nil(t, err)
+
+	// Skip the messages published to before that were already received.
+	m, ok := receiveWhilePublishing(t, s, publish(after))
+	for ok && m.Channel == before {
+		m, ok = receiveWhilePublishing(t, s, publish(after))
+	}
+	assert.True(t, ok)
+	assert.Equal(t, after, m.Channel)
+}
+
+// renameConn sends everything to the embedded connection, but sends from instead of to.
+type renameConn struct {
+	redis.Conn
+	from, to string
+}
+
+func (c renameConn) Send(cmd string, args ...interface{}) error {
+	if cmd == c.from {
+		cmd = c.to
+	}
+	return c.Conn.Send(cmd, args...)
+}
+
+func (c renameConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+func TestSubscriber_ErrorReply(t *testing.T) {
+	dials := 0
+	s := pubsub.NewSubscriber(context.Background(), func() (redis.Conn, error) {
+		dials++
+		c, err := dial()
+		return renameConn{Conn: c, from: "SUBSCRIBE", to: "NOSUCHCOMMAND"}, err
+	}, pubsub.SubscriberOptions{ReconnectDelay: 10 * time.Millisecond})
+	defer s.Close()
+
+	assert.Nil(t, s.Err())
+	assert.Nil(t, s.Subscribe(test.RandomKey()))
+
+	select {
+	case _, ok := <-s.Messages():
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Messages was not closed")
+	}
+	assert.IsType(t, redis.Error(""), s.Err())
+	assert.Equal(t, s.Err(), s.Close())
+	assert.Equal(t, 1, dials)
+	assert.Equal(t, pubsub.ErrClosed, s.Subscribe(test.RandomKey()))
+}
+
+func TestSubscriber_HealthCheck(t *testing.T) {
+	s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{
+		HealthCheckInterval: 100 * time.Millisecond,
+	})
+	defer s.Close()
+
+	channel := test.RandomKey()
+	assert.Nil(t, s.Subscribe(channel))
+
+	// The pings keep the connection open while no messages are published.
+	time.Sleep(500 * time.Millisecond)
+
+	value, err := pubsub.Publish(context.Background(), redistypes.SingleConn(conn), channel, "hello")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	m, ok := <-s.Messages()
+	assert.True(t, ok)
+	assert.Equal(t, channel, m.Channel)
+}
+
+func TestSubscriber_Close(t *testing.T) {
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := pubsub.NewSubscriber(ctx, dial, pubsub.SubscriberOptions{})
+		assert.Nil(t, s.Subscribe(test.RandomKey()))
+
+		cancel()
+
+		select {
+		case _, ok := <-s.Messages():
+			assert.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Error("Messages was not closed")
+		}
+		assert.Equal(t, pubsub.ErrClosed, s.Subscribe(test.RandomKey()))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		s := pubsub.NewSubscriber(context.Background(), dial, pubsub.SubscriberOptions{})
+		assert.Nil(t, s.Subscribe(test.RandomKey()))
+
+		assert.Nil(t, s.Close())
+
+		_, ok := <-s.Messages()
+		assert.False(t, ok)
+		assert.Equal(t, pubsub.ErrClosed, s.Subscribe(test.RandomKey()))
+	})
+}
@@ -2,12 +2,82 @@
 package redistypes
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
 
+	"github.com/MasterOfBinary/redistypes/internal"
 	"github.com/garyburd/redigo/redis"
 )
 
+var (
+	// ErrKeyNotFound is returned by methods that inspect a key when the key does
+	// not exist in Redis.
+	ErrKeyNotFound = errors.New("Key does not exist")
+
+	// ErrNoExpiry is returned by methods that inspect a key's timeout when the key
+	// exists but has no timeout associated with it.
+	ErrNoExpiry = errors.New("Key has no associated expiry")
+)
+
+// ExpireCondition is a condition under which the expiry methods set a timeout on
+// a key. It requires Redis 7.0 or later.
+type ExpireCondition string
+
+const (
+	// ExpireNX sets the timeout only if the key has no timeout.
+	ExpireNX ExpireCondition = "NX"
+
+	// ExpireXX sets the timeout only if the key already has a timeout.
+	ExpireXX ExpireCondition = "XX"
+
+	// ExpireGT sets the timeout only if it is greater than the current timeout. A
+	// key without a timeout is treated as having an infinite one.
+	ExpireGT ExpireCondition = "GT"
+
+	// ExpireLT sets the timeout only if it is less than the current timeout. A key
+	// without a timeout is treated as having an infinite one.
+	ExpireLT ExpireCondition = "LT"
+)
+
+// KeyInfo describes a key in Redis and how its value is stored. It is returned by
+// Type.Inspect.
+type KeyInfo struct {
+	// Type is the Redis type of the value, such as "list", "set" or "string".
+	//
+	// See https://redis.io/commands/type.
+	Type string
+
+	// Encoding is the internal representation of the value, such as "listpack"
+	// or "quicklist".
+	//
+	// See https://redis.io/commands/object-encoding.
+	Encoding string
+
+	// IdleTime is the time since the key was last accessed. Redis only tracks it
+	// when the maxmemory-policy is not an LFU policy; otherwise it is 0.
+	//
+	// See https://redis.io/commands/object-idletime.
+	IdleTime time.Duration
+
+	// Frequency is the logarithmic access frequency counter of the key. Redis only
+	// tracks it when the maxmemory-policy is an LFU policy; otherwise it is 0.
+	//
+	// See https://redis.io/commands/object-freq.
+	Frequency int64
+
+	// RefCount is the number of references to the value.
+	//
+	// See https://redis.io/commands/object-refcount.
+	RefCount int64
+
+	// MemoryUsage is the number of bytes used to store the key and its value.
+	//
+	// See https://redis.io/commands/memory-usage.
+	MemoryUsage int64
+}
+
 // Type is an interface containing methods that every Redis type supports. These
 // methods operate on keys in Redis with name equal to Name(), and they do not
 // depend on the type of value stored in the key.
@@ -15,12 +85,26 @@ type Type interface {
 	// Name returns the name of the key in Redis.
 	Name() string
 
+	// Copy implements the Redis command COPY. It copies the value stored at the key
+	// to destination. If destination already exists, it is only overwritten if
+	// replace is true. Copy returns true if the value was copied, or false otherwise.
+	//
+	// See https://redis.io/commands/copy.
+	Copy(destination string, replace bool) (bool, error)
+
 	// Delete implements the Redis command DEL. If a key exists in Redis, it is
 	// deleted and true is returned. If it does not exist, false is returned.
 	//
 	// See https://redis.io/commands/del.
 	Delete() (bool, error)
 
+	// Dump implements the Redis command DUMP. It returns the value stored at the key
+	// serialized in a Redis-specific format, which can be restored with Restore. If
+	// the key does not exist, ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/dump.
+	Dump() ([]byte, error)
+
 	// Exists implements the Redis command EXISTS. It determines if the key exists in
 	// Redis. Exists returns true if it exists or false if it does not.
 	//
@@ -37,9 +121,34 @@ type Type interface {
 	// For a more precise version of Expire, see PExpire.
 	//
 	// Expire returns true if the key exists and the timeout was set, or false otherwise.
+	// If conditions are given, the timeout is only set if they are met.
 	//
 	// See https://redis.io/commands/expire.
-	Expire(timeout time.Duration) (bool, error)
+	Expire(timeout time.Duration, conditions ...ExpireCondition) (bool, error)
+
+	// ExpireAt implements the Redis command EXPIREAT. It works like Expire, except
+	// the key is deleted automatically at the absolute time t.
+	//
+	// ExpireAt uses the Redis command EXPIREAT, which has second precision. If t
+	// has more precision than that, an error is returned. For a more precise
+	// version of ExpireAt, see PExpireAt.
+	//
+	// See https://redis.io/commands/expireat.
+	ExpireAt(t time.Time, conditions ...ExpireCondition) (bool, error)
+
+	// ExpireTime implements the Redis command EXPIRETIME. It returns the absolute
+	// time at which the key will expire, with second precision. If the key does
+	// not exist, ErrKeyNotFound is returned. If it exists but has no timeout,
+	// ErrNoExpiry is returned.
+	//
+	// See https://redis.io/commands/expiretime.
+	ExpireTime() (time.Time, error)
+
+	// Inspect returns information about the key using the Redis commands TYPE,
+	// OBJECT and MEMORY USAGE. If the key does not exist, ErrKeyNotFound is returned.
+	//
+	// See KeyInfo for details about the information returned.
+	Inspect() (KeyInfo, error)
 
 	// PExpire implements the Redis command PEXPIRE. It sets a timeout (given in
 	// milliseconds) on the key, after which the key is deleted automatically. To
@@ -51,9 +160,29 @@ type Type interface {
 	// For a less precise version of PExpire, see Expire.
 	//
 	// PExpire returns true if the key exists and the timeout was set, or false otherwise.
+	// If conditions are given, the timeout is only set if they are met.
 	//
 	// See https://redis.io/commands/pexpire.
-	PExpire(timeout time.Duration) (bool, error)
+	PExpire(timeout time.Duration, conditions ...ExpireCondition) (bool, error)
+
+	// PExpireAt implements the Redis command PEXPIREAT. It works like ExpireAt,
+	// except it has millisecond precision. If t has more precision than that, an
+	// error is returned.
+	//
+	// See https://redis.io/commands/pexpireat.
+	PExpireAt(t time.Time, conditions ...ExpireCondition) (bool, error)
+
+	// PExpireTime implements the Redis command PEXPIRETIME. It works like
+	// ExpireTime, except it has millisecond precision.
+	//
+	// See https://redis.io/commands/pexpiretime.
+	PExpireTime() (time.Time, error)
+
+	// PTTL implements the Redis command PTTL. It works like TTL, except it has
+	// millisecond precision.
+	//
+	// See https://redis.io/commands/pttl.
+	PTTL() (time.Duration, error)
 
 	// Persist implements the Redis command PERSIST. It causes a volatile key to persist.
 	//
@@ -72,66 +201,329 @@ type Type interface {
 	//
 	// See https://redis.io/commands/renamenx.
 	RenameNX(newkey string) (bool, error)
+
+	// Restore implements the Redis command RESTORE. It creates the key from payload,
+	// which was obtained from Dump. If ttl is not 0, it is set as the timeout of the
+	// key. If the key already exists, it is only overwritten if replace is true;
+	// otherwise an error is returned.
+	//
+	// Restore has millisecond precision. If ttl has more precision than that, an
+	// error is returned.
+	//
+	// See https://redis.io/commands/restore.
+	Restore(payload []byte, ttl time.Duration, replace bool) error
+
+	// TTL implements the Redis command TTL. It returns the remaining time to live
+	// of the key, with second precision. If the key does not exist, ErrKeyNotFound
+	// is returned. If it exists but has no timeout, ErrNoExpiry is returned.
+	//
+	// See https://redis.io/commands/ttl.
+	TTL() (time.Duration, error)
+
+	// WithContext returns a copy of the Type that uses ctx for its commands. If ctx
+	// is done before a command is sent, ctx.Err() is returned. If ctx has a deadline,
+	// it is used as the timeout for the reply.
+	//
+	// The copy refers to the same key, so renaming one renames the other.
+	WithContext(ctx context.Context) Type
 }
 
 type redisType struct {
-	conn redis.Conn
+	provider ConnProvider
+	key      *key
+	ctx      context.Context
+}
+
+// key holds the name of a Redis key. It is shared by the copies of a Type made by
+// WithContext, so that Rename and RenameNX update all of them.
+type key struct {
+	mu   sync.RWMutex
 	name string
 }
 
+// NewRedisType creates a Type given redigo connection conn and name. The Redis key
+// used to identify the Type will be name.
 func NewRedisType(conn redis.Conn, name string) Type {
+	return NewRedisTypeFromProvider(SingleConn(conn), name)
+}
+
+// NewRedisTypeFromProvider creates a Type given ConnProvider p and name. Each command
+// borrows a connection from p. The Redis key used to identify the Type will be name.
+func NewRedisTypeFromProvider(p ConnProvider, name string) Type {
 	return &redisType{
-		conn: conn,
-		name: name,
+		provider: p,
+		key:      &key{name: name},
+		ctx:      context.Background(),
 	}
 }
 
 func (r redisType) Name() string {
-	return r.name
+	r.key.mu.RLock()
+	defer r.key.mu.RUnlock()
+	return r.key.name
+}
+
+func (r *redisType) Copy(destination string, replace bool) (bool, error) {
+	args := []interface{}{r.Name(), destination}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	return redis.Bool(internal.Do(r.ctx, r.provider, "COPY", args...))
 }
 
 func (r *redisType) Delete() (bool, error) {
-	return redis.Bool(r.conn.Do("DEL", r.name))
+	return redis.Bool(internal.Do(r.ctx, r.provider, "DEL", r.Name()))
+}
+
+func (r *redisType) Dump() ([]byte, error) {
+	payload, err := redis.Bytes(internal.Do(r.ctx, r.provider, "DUMP", r.Name()))
+	if err == redis.ErrNil {
+		return nil, ErrKeyNotFound
+	}
+	return payload, err
 }
 
 func (r *redisType) Exists() (bool, error) {
-	return redis.Bool(r.conn.Do("EXISTS", r.name))
+	return redis.Bool(internal.Do(r.ctx, r.provider, "EXISTS", r.Name()))
+}
+
+func (r *redisType) Expire(timeout time.Duration, conditions ...ExpireCondition) (bool, error) {
+	seconds, err := durationSeconds(timeout)
+	if err != nil {
+		return false, err
+	}
+
+	return redis.Bool(internal.Do(r.ctx, r.provider, "EXPIRE", expireArgs(r.Name(), seconds, conditions)...))
+}
+
+func (r *redisType) ExpireAt(t time.Time, conditions ...ExpireCondition) (bool, error) {
+	seconds, err := timeSeconds(t)
+	if err != nil {
+		return false, err
+	}
+
+	return redis.Bool(internal.Do(r.ctx, r.provider, "EXPIREAT", expireArgs(r.Name(), seconds, conditions)...))
+}
+
+func (r *redisType) ExpireTime() (time.Time, error) {
+	seconds, err := redis.Int64(internal.Do(r.ctx, r.provider, "EXPIRETIME", r.Name()))
+	if err != nil {
+		return time.Time{}, err
+	} else if err = expiryError(seconds); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
 }
 
-func (r *redisType) Expire(timeout time.Duration) (bool, error) {
-	seconds := int64(timeout.Seconds())
-	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
-		return false, errors.New("Duration is not a multiple of one second")
+func (r *redisType) Inspect() (KeyInfo, error) {
+	var info KeyInfo
+
+	conn, err := internal.GetConn(r.ctx, r.provider)
+	if err != nil {
+		return info, err
+	}
+	defer conn.Close()
+
+	name := r.Name()
+
+	keyType, err := redis.String(internal.DoContext(r.ctx, conn, "TYPE", name))
+	if err != nil {
+		return info, err
+	} else if keyType == "none" {
+		return info, ErrKeyNotFound
+	}
+	info.Type = keyType
+
+	if info.Encoding, err = redis.String(internal.DoContext(r.ctx, conn, "OBJECT", "ENCODING", name)); err != nil {
+		return info, inspectError(err)
+	}
+	if info.RefCount, err = redis.Int64(internal.DoContext(r.ctx, conn, "OBJECT", "REFCOUNT", name)); err != nil {
+		return info, inspectError(err)
+	}
+	if info.MemoryUsage, err = redis.Int64(internal.DoContext(r.ctx, conn, "MEMORY", "USAGE", name)); err != nil {
+		return info, inspectError(err)
 	}
 
-	return redis.Bool(r.conn.Do("EXPIRE", r.name, seconds))
+	// Only one of IDLETIME and FREQ is available, depending on the maxmemory-policy.
+	// Redis replies with an error to the other one, which is ignored.
+	idle, err := redis.Int64(internal.DoContext(r.ctx, conn, "OBJECT", "IDLETIME", name))
+	if _, ok := err.(redis.Error); err != nil && !ok {
+		return info, inspectError(err)
+	}
+	info.IdleTime = time.Duration(idle) * time.Second
+
+	info.Frequency, err = redis.Int64(internal.DoContext(r.ctx, conn, "OBJECT", "FREQ", name))
+	if _, ok := err.(redis.Error); err != nil && !ok {
+		return info, inspectError(err)
+	}
+
+	return info, nil
 }
 
-func (r *redisType) PExpire(timeout time.Duration) (bool, error) {
-	ms := int64(timeout.Nanoseconds() / 1000000)
-	if timeout.Nanoseconds()-ms*time.Millisecond.Nanoseconds() != 0 {
-		return false, errors.New("Duration is not a multiple of one millisecond")
+func (r *redisType) PExpire(timeout time.Duration, conditions ...ExpireCondition) (bool, error) {
+	ms, err := durationMilliseconds(timeout)
+	if err != nil {
+		return false, err
 	}
 
-	return redis.Bool(r.conn.Do("PEXPIRE", r.name, ms))
+	return redis.Bool(internal.Do(r.ctx, r.provider, "PEXPIRE", expireArgs(r.Name(), ms, conditions)...))
+}
+
+func (r *redisType) PExpireAt(t time.Time, conditions ...ExpireCondition) (bool, error) {
+	ms, err := timeMilliseconds(t)
+	if err != nil {
+		return false, err
+	}
+
+	return redis.Bool(internal.Do(r.ctx, r.provider, "PEXPIREAT", expireArgs(r.Name(), ms, conditions)...))
+}
+
+func (r *redisType) PExpireTime() (time.Time, error) {
+	ms, err := redis.Int64(internal.Do(r.ctx, r.provider, "PEXPIRETIME", r.Name()))
+	if err != nil {
+		return time.Time{}, err
+	} else if err = expiryError(ms); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+func (r *redisType) PTTL() (time.Duration, error) {
+	reply, err := internal.Do(r.ctx, r.provider, "PTTL", r.Name())
+	return expiryDuration(reply, err, time.Millisecond)
 }
 
 func (r *redisType) Persist() (bool, error) {
-	return redis.Bool(r.conn.Do("PERSIST", r.name))
+	return redis.Bool(internal.Do(r.ctx, r.provider, "PERSIST", r.Name()))
 }
 
 func (r *redisType) Rename(newkey string) error {
-	_, err := r.conn.Do("RENAME", r.name, newkey)
-	if err != nil {
-		r.name = newkey
+	_, err := internal.Do(r.ctx, r.provider, "RENAME", r.Name(), newkey)
+	if err == nil {
+		r.setName(newkey)
 	}
 	return err
 }
 
 func (r *redisType) RenameNX(newkey string) (bool, error) {
-	success, err := redis.Bool(r.conn.Do("RENAMENX", r.name, newkey))
+	success, err := redis.Bool(internal.Do(r.ctx, r.provider, "RENAMENX", r.Name(), newkey))
 	if success {
-		r.name = newkey
+		r.setName(newkey)
 	}
 	return success, err
 }
+
+func (r *redisType) Restore(payload []byte, ttl time.Duration, replace bool) error {
+	ms, err := durationMilliseconds(ttl)
+	if err != nil {
+		return err
+	}
+
+	args := []interface{}{r.Name(), ms, payload}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	_, err = internal.Do(r.ctx, r.provider, "RESTORE", args...)
+	return err
+}
+
+func (r *redisType) TTL() (time.Duration, error) {
+	reply, err := internal.Do(r.ctx, r.provider, "TTL", r.Name())
+	return expiryDuration(reply, err, time.Second)
+}
+
+func (r *redisType) setName(name string) {
+	r.key.mu.Lock()
+	r.key.name = name
+	r.key.mu.Unlock()
+}
+
+func (r *redisType) WithContext(ctx context.Context) Type {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// durationSeconds converts d to seconds. If d is not a multiple of one second, an
+// error is returned.
+func durationSeconds(d time.Duration) (int64, error) {
+	seconds := int64(d.Seconds())
+	if d.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return 0, errors.New("Duration is not a multiple of one second")
+	}
+	return seconds, nil
+}
+
+// durationMilliseconds converts d to milliseconds. If d is not a multiple of one
+// millisecond, an error is returned.
+func durationMilliseconds(d time.Duration) (int64, error) {
+	ms := int64(d.Nanoseconds() / 1000000)
+	if d.Nanoseconds()-ms*time.Millisecond.Nanoseconds() != 0 {
+		return 0, errors.New("Duration is not a multiple of one millisecond")
+	}
+	return ms, nil
+}
+
+// timeSeconds converts t to a Unix time in seconds. If t is not a multiple of one
+// second, an error is returned.
+func timeSeconds(t time.Time) (int64, error) {
+	if t.Nanosecond() != 0 {
+		return 0, errors.New("Time is not a multiple of one second")
+	}
+	return t.Unix(), nil
+}
+
+// timeMilliseconds converts t to a Unix time in milliseconds. If t is not a multiple
+// of one millisecond, an error is returned.
+func timeMilliseconds(t time.Time) (int64, error) {
+	if t.Nanosecond()%int(time.Millisecond) != 0 {
+		return 0, errors.New("Time is not a multiple of one millisecond")
+	}
+	return t.UnixNano() / int64(time.Millisecond), nil
+}
+
+// expireArgs returns the arguments for one of the EXPIRE family of commands.
+func expireArgs(name string, value int64, conditions []ExpireCondition) []interface{} {
+	args := make([]interface{}, 0, len(conditions)+2)
+	args = append(args, name, value)
+	for _, condition := range conditions {
+		args = append(args, string(condition))
+	}
+	return args
+}
+
+// expiryDuration converts a reply of TTL or PTTL, given in unit, into a duration.
+func expiryDuration(reply interface{}, err error, unit time.Duration) (time.Duration, error) {
+	n, err := redis.Int64(reply, err)
+	if err != nil {
+		return 0, err
+	} else if err = expiryError(n); err != nil {
+		return 0, err
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// inspectError converts a nil reply, returned when the key is deleted while it is being
+// inspected, into ErrKeyNotFound.
+func inspectError(err error) error {
+	if err == redis.ErrNil {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// expiryError converts the negative replies of TTL, PTTL, EXPIRETIME and PEXPIRETIME
+// into errors.
+func expiryError(reply int64) error {
+	switch reply {
+	case -2:
+		return ErrKeyNotFound
+	case -1:
+		return ErrNoExpiry
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package redistypes_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -22,6 +23,49 @@ func TestRedisType_Name(t *testing.T) {
 	assert.Equal(t, name, r.Name())
 }
 
+func TestRedisType_Copy(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	destination := test.RandomKey()
+	defer test.DeleteKey(destination, conn)
+
+	t.Run("non-existing key", func(t *testing.T) {
+		success, err := r.Copy(destination, false)
+		assert.Nil(t, err)
+		assert.False(t, success)
+	})
+
+	t.Run("non-existing destination", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		success, err := r.Copy(destination, false)
+		assert.Nil(t, err)
+		assert.True(t, success)
+
+		value, _ := conn.Do("GET", destination)
+		test.AssertEqual(t, 1, value)
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, _ = conn.Do("SET", destination, 2)
+
+		success, err := r.Copy(destination, false)
+		assert.Nil(t, err)
+		assert.False(t, success)
+
+		value, _ := conn.Do("GET", destination)
+		test.AssertEqual(t, 2, value)
+
+		success, err = r.Copy(destination, true)
+		assert.Nil(t, err)
+		assert.True(t, success)
+
+		value, _ = conn.Do("GET", destination)
+		test.AssertEqual(t, 1, value)
+	})
+}
+
 func TestRedisType_Delete(t *testing.T) {
 	r := redistypes.NewRedisType(conn, test.RandomKey())
 	defer r.Delete()
@@ -44,6 +88,23 @@ func TestRedisType_Delete(t *testing.T) {
 	})
 }
 
+func TestRedisType_Dump(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := r.Dump()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		payload, err := r.Dump()
+		assert.Nil(t, err)
+		assert.NotEmpty(t, payload)
+	})
+}
+
 func TestRedisType_Exists(t *testing.T) {
 	r := redistypes.NewRedisType(conn, test.RandomKey())
 	defer r.Delete()
@@ -149,6 +210,113 @@ func TestRedisType_Expire(t *testing.T) {
 	})
 }
 
+func TestRedisType_ExpireAt(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		success, err := r.ExpireAt(time.Now().Add(time.Minute).Truncate(time.Second))
+		assert.Nil(t, err)
+		assert.False(t, success)
+	})
+
+	t.Run("sub-second precision", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.ExpireAt(time.Now().Truncate(time.Second).Add(time.Minute + 5*time.Millisecond))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		at := time.Now().Add(time.Minute).Truncate(time.Second)
+		success, err := r.ExpireAt(at)
+		assert.Nil(t, err)
+		assert.True(t, success)
+
+		got, err := r.ExpireTime()
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(got))
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		at := time.Now().Add(time.Minute).Truncate(time.Second)
+
+		success, err := r.ExpireAt(at, redistypes.ExpireXX)
+		assert.Nil(t, err)
+		assert.False(t, success)
+
+		success, err = r.ExpireAt(at, redistypes.ExpireNX)
+		assert.Nil(t, err)
+		assert.True(t, success)
+
+		success, err = r.ExpireAt(at.Add(-time.Second), redistypes.ExpireGT)
+		assert.Nil(t, err)
+		assert.False(t, success)
+
+		success, err = r.ExpireAt(at.Add(-time.Second), redistypes.ExpireLT)
+		assert.Nil(t, err)
+		assert.True(t, success)
+	})
+}
+
+func TestRedisType_ExpireTime(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := r.ExpireTime()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.ExpireTime()
+		assert.Equal(t, redistypes.ErrNoExpiry, err)
+	})
+
+	t.Run("expiring key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, _ = r.Expire(10 * time.Second)
+
+		got, err := r.ExpireTime()
+		assert.Nil(t, err)
+		assert.WithinDuration(t, time.Now().Add(10*time.Second), got, 2*time.Second)
+	})
+}
+
+func TestRedisType_Inspect(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := r.Inspect()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("string key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+
+		info, err := r.Inspect()
+		assert.Nil(t, err)
+		assert.Equal(t, "string", info.Type)
+		assert.Equal(t, "int", info.Encoding)
+		assert.True(t, info.MemoryUsage > 0)
+	})
+
+	t.Run("list key", func(t *testing.T) {
+		_, _ = r.Delete()
+		_, _ = conn.Do("RPUSH", r.Name(), "a", "b", "c")
+
+		info, err := r.Inspect()
+		assert.Nil(t, err)
+		assert.Equal(t, "list", info.Type)
+		assert.NotEmpty(t, info.Encoding)
+		assert.EqualValues(t, 1, info.RefCount)
+		assert.True(t, info.MemoryUsage > 0)
+	})
+}
+
 func TestRedisType_PExpire(t *testing.T) {
 	r := redistypes.NewRedisType(conn, test.RandomKey())
 	defer r.Delete()
@@ -236,6 +404,85 @@ func TestRedisType_PExpire(t *testing.T) {
 	})
 }
 
+func TestRedisType_PExpireAt(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		success, err := r.PExpireAt(time.Now().Add(time.Minute).Truncate(time.Millisecond))
+		assert.Nil(t, err)
+		assert.False(t, success)
+	})
+
+	t.Run("sub-millisecond precision", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.PExpireAt(time.Now().Truncate(time.Millisecond).Add(time.Minute + 5*time.Nanosecond))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		at := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+		success, err := r.PExpireAt(at)
+		assert.Nil(t, err)
+		assert.True(t, success)
+
+		got, err := r.PExpireTime()
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(got))
+	})
+}
+
+func TestRedisType_PExpireTime(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := r.PExpireTime()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.PExpireTime()
+		assert.Equal(t, redistypes.ErrNoExpiry, err)
+	})
+
+	t.Run("expiring key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, _ = r.PExpire(1500 * time.Millisecond)
+
+		got, err := r.PExpireTime()
+		assert.Nil(t, err)
+		assert.WithinDuration(t, time.Now().Add(1500*time.Millisecond), got, 500*time.Millisecond)
+	})
+}
+
+func TestRedisType_PTTL(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := r.PTTL()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.PTTL()
+		assert.Equal(t, redistypes.ErrNoExpiry, err)
+	})
+
+	t.Run("expiring key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, _ = r.PExpire(1500 * time.Millisecond)
+
+		ttl, err := r.PTTL()
+		assert.Nil(t, err)
+		assert.True(t, ttl > time.Second && ttl <= 1500*time.Millisecond)
+	})
+}
+
 func TestRedisType_Persist(t *testing.T) {
 	r := redistypes.NewRedisType(conn, test.RandomKey())
 	defer r.Delete()
@@ -284,6 +531,7 @@ func TestRedisType_Rename(t *testing.T) {
 
 		err := r.Rename(newname)
 		assert.NotNil(t, err)
+		assert.Equal(t, oldname, r.Name())
 	})
 
 	t.Run("non-existing new key", func(t *testing.T) {
@@ -297,6 +545,7 @@ func TestRedisType_Rename(t *testing.T) {
 
 		err := r.Rename(newname)
 		assert.Nil(t, err)
+		assert.Equal(t, newname, r.Name())
 
 		value, err := conn.Do("GET", oldname)
 		assert.Nil(t, err)
@@ -318,6 +567,7 @@ func TestRedisType_Rename(t *testing.T) {
 
 		err := r.Rename(newname)
 		assert.Nil(t, err)
+		assert.Equal(t, newname, r.Name())
 
 		value, err := conn.Do("GET", oldname)
 		assert.Nil(t, err)
@@ -382,6 +632,104 @@ func TestRedisType_RenameNX(t *testing.T) {
 	})
 }
 
+func TestRedisType_Restore(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	_, _ = conn.Do("SET", r.Name(), 1)
+	payload, _ := r.Dump()
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 2)
+		err := r.Restore(payload, 0, false)
+		assert.NotNil(t, err)
+
+		value, _ := conn.Do("GET", r.Name())
+		test.AssertEqual(t, 2, value)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 2)
+		err := r.Restore(payload, 0, true)
+		assert.Nil(t, err)
+
+		value, _ := conn.Do("GET", r.Name())
+		test.AssertEqual(t, 1, value)
+
+		_, err = r.TTL()
+		assert.Equal(t, redistypes.ErrNoExpiry, err)
+	})
+
+	t.Run("with ttl", func(t *testing.T) {
+		_, _ = r.Delete()
+		err := r.Restore(payload, 10*time.Second, false)
+		assert.Nil(t, err)
+
+		ttl, err := r.PTTL()
+		assert.Nil(t, err)
+		assert.True(t, ttl > 8*time.Second && ttl <= 10*time.Second)
+	})
+
+	t.Run("sub-millisecond ttl", func(t *testing.T) {
+		_, _ = r.Delete()
+		err := r.Restore(payload, time.Second+5*time.Nanosecond, false)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRedisType_TTL(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := r.TTL()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.TTL()
+		assert.Equal(t, redistypes.ErrNoExpiry, err)
+	})
+
+	t.Run("expiring key", func(t *testing.T) {
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, _ = r.Expire(10 * time.Second)
+
+		ttl, err := r.TTL()
+		assert.Nil(t, err)
+		assert.True(t, ttl > 8*time.Second && ttl <= 10*time.Second)
+	})
+}
+
+func TestRedisType_WithContext(t *testing.T) {
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+	defer r.Delete()
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _ = conn.Do("SET", r.Name(), 1)
+		_, err := r.WithContext(ctx).Exists()
+		assert.Equal(t, context.Canceled, err)
+
+		exists, err := r.Exists()
+		assert.Nil(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("context with deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, _ = conn.Do("SET", r.Name(), 1)
+		exists, err := r.WithContext(ctx).Exists()
+		assert.Nil(t, err)
+		assert.True(t, exists)
+	})
+}
+
 func TestMain(m *testing.M) {
 	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
 	if err != nil {
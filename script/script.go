@@ -0,0 +1,199 @@
+// Package script contains a registry of Lua scripts that are run on the Redis server. Scripts
+// are called with types from redistypes as their keys, and are run using their SHA1 digest
+// whenever possible. For more information about scripting, see the Redis documentation.
+package script
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// Script is a Lua script registered in a Registry.
+type Script struct {
+	name     string
+	src      string
+	hash     string
+	registry *Registry
+}
+
+// Name returns the name the script was registered with.
+func (s *Script) Name() string {
+	return s.name
+}
+
+// Hash returns the SHA1 digest of the script, which Redis uses to identify it.
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Do runs the script on the Redis server with the names of keys as KEYS and args as
+// ARGV, and returns its reply. The reply can be converted with the redigo helper
+// functions, or with Int64, String and Values.
+//
+// Do uses the Redis command EVALSHA. If the script is not in the script cache, it
+// falls back to the Redis command EVAL, which also adds it to the cache.
+//
+// See https://redis.io/commands/evalsha.
+func (s *Script) Do(ctx context.Context, keys []redistypes.Type, args ...interface{}) (interface{}, error) {
+	conn, err := internal.GetConn(ctx, s.registry.provider)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = s.registry.loadPending(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	reply, err := internal.DoContext(ctx, conn, "EVALSHA", s.args(s.hash, keys, args)...)
+	if e, ok := err.(redis.Error); ok && strings.HasPrefix(string(e), "NOSCRIPT ") {
+		reply, err = internal.DoContext(ctx, conn, "EVAL", s.args(s.src, keys, args)...)
+	}
+	return reply, err
+}
+
+// Int64 runs the script like Do and converts its reply to an int64.
+func (s *Script) Int64(ctx context.Context, keys []redistypes.Type, args ...interface{}) (int64, error) {
+	return redis.Int64(s.Do(ctx, keys, args...))
+}
+
+// String runs the script like Do and converts its reply to a string.
+func (s *Script) String(ctx context.Context, keys []redistypes.Type, args ...interface{}) (string, error) {
+	return redis.String(s.Do(ctx, keys, args...))
+}
+
+// Values runs the script like Do and converts its reply, which must be an array, to a
+// slice.
+func (s *Script) Values(ctx context.Context, keys []redistypes.Type, args ...interface{}) ([]interface{}, error) {
+	return redis.Values(s.Do(ctx, keys, args...))
+}
+
+// args returns the arguments to EVAL or EVALSHA, where script is the source or hash.
+func (s *Script) args(script string, keys []redistypes.Type, args []interface{}) []interface{} {
+	evalArgs := make([]interface{}, 0, 2+len(keys)+len(args))
+	evalArgs = append(evalArgs, script, len(keys))
+	for _, key := range keys {
+		evalArgs = append(evalArgs, key.Name())
+	}
+	return append(evalArgs, args...)
+}
+
+// Registry holds a set of named Lua scripts, which are run using its ConnProvider. The
+// scripts are loaded into the script cache on the first connection the Registry uses
+// after they are registered, so they can be run with EVALSHA right away. It is safe for
+// concurrent use.
+type Registry struct {
+	provider redistypes.ConnProvider
+
+	mu      sync.RWMutex
+	scripts map[string]*Script
+	pending map[string]*Script
+}
+
+// NewRegistry creates an empty Registry whose scripts borrow connections from p.
+func NewRegistry(p redistypes.ConnProvider) *Registry {
+	return &Registry{
+		provider: p,
+		scripts:  make(map[string]*Script),
+		pending:  make(map[string]*Script),
+	}
+}
+
+// Register adds the script src to the registry under name and returns it. If a script
+// is already registered with the same name, it is replaced. The script is loaded with
+// the Redis command SCRIPT LOAD the next time the registry gets a connection, which is
+// when any of its scripts is run or Load is called.
+func (r *Registry) Register(name, src string) *Script {
+	sum := sha1.Sum([]byte(src))
+	s := &Script{
+		name:     name,
+		src:      src,
+		hash:     hex.EncodeToString(sum[:]),
+		registry: r,
+	}
+
+	r.mu.Lock()
+	r.scripts[name] = s
+	r.pending[name] = s
+	r.mu.Unlock()
+
+	return s
+}
+
+// Script returns the script registered under name, or nil and false if there is none.
+func (r *Registry) Script(name string) (*Script, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.scripts[name]
+	return s, ok
+}
+
+// Load implements the Redis command SCRIPT LOAD. It loads every registered script into
+// the script cache, including the ones that were already loaded. It can be called to
+// load the scripts again after the script cache is flushed; scripts that are not in the
+// cache are loaded when they are first run anyway.
+//
+// See https://redis.io/commands/script-load.
+func (r *Registry) Load(ctx context.Context) error {
+	conn, err := internal.GetConn(ctx, r.provider)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err = load(ctx, conn, r.scripts); err != nil {
+		return err
+	}
+	r.pending = make(map[string]*Script)
+	return nil
+}
+
+// loadPending loads the scripts that were registered since the registry last got a
+// connection.
+func (r *Registry) loadPending(ctx context.Context, conn redis.Conn) error {
+	r.mu.RLock()
+	n := len(r.pending)
+	r.mu.RUnlock()
+	if n == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A script that Redis rejects is not retried; its error is returned when it is run.
+	err := load(ctx, conn, r.pending)
+	if _, ok := err.(redis.Error); err != nil && !ok {
+		return err
+	}
+	r.pending = make(map[string]*Script)
+	return nil
+}
+
+// load sends SCRIPT LOAD for each of scripts on conn. If Redis rejects a script, the
+// rest are still loaded and the first error is returned.
+func load(ctx context.Context, conn redis.Conn, scripts map[string]*Script) error {
+	var scriptErr error
+	for _, s := range scripts {
+		_, err := internal.DoContext(ctx, conn, "SCRIPT", "LOAD", s.src)
+		if _, ok := err.(redis.Error); ok {
+			if scriptErr == nil {
+				scriptErr = err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+	return scriptErr
+}
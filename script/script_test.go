@@ -0,0 +1,188 @@
+package script_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/list"
+	"github.com/MasterOfBinary/redistypes/script"
+	"github.com/MasterOfBinary/redistypes/set"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+// popAdd pops a value from the list in KEYS[1] and adds it to the set in KEYS[2].
+const popAdd = `
+local value = redis.call("LPOP", KEYS[1])
+if value then
+	redis.call("SADD", KEYS[2], value)
+end
+return value
+`
+
+func ExampleScript_Do() {
+	netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+
+	conn := redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	incrBy := registry.Register("incrby", `return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+
+	r := redistypes.NewRedisType(conn, test.RandomKey())
+
+	value, _ := incrBy.Int64(context.Background(), []redistypes.Type{r}, 5)
+	fmt.Println(value)
+
+	_, _ = r.Delete()
+
+	// Output: 5
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+
+	s := registry.Register("ping", `return "PONG"`)
+	assert.Equal(t, "ping", s.Name())
+	assert.Equal(t, "b1c643a4af6844b2672409c113ef4ae61fbbc3ba", s.Hash())
+
+	got, ok := registry.Script("ping")
+	assert.True(t, ok)
+	assert.Equal(t, s, got)
+
+	_, ok = registry.Script("pong")
+	assert.False(t, ok)
+}
+
+func TestRegistry_Load(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	s := registry.Register("ping", `return "PONG"`)
+
+	_, _ = conn.Do("SCRIPT", "FLUSH")
+
+	err := registry.Load(context.Background())
+	assert.Nil(t, err)
+
+	exists, err := redis.Ints(conn.Do("SCRIPT", "EXISTS", s.Hash()))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, exists)
+}
+
+func TestRegistry_preload(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	ping := registry.Register("ping", `return "PONG"`)
+
+	_, _ = conn.Do("SCRIPT", "FLUSH")
+
+	echo := registry.Register("echo", `return ARGV[1]`)
+	_ = registry.Register("invalid", `return (`)
+
+	value, err := ping.String(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", value)
+
+	exists, err := redis.Ints(conn.Do("SCRIPT", "EXISTS", ping.Hash(), echo.Hash()))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 1}, exists)
+}
+
+func TestScript_Do(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	s := registry.Register("popadd", popAdd)
+
+	l := list.NewRedisList(conn, test.RandomKey())
+	defer l.Base().Delete()
+
+	dest := set.NewRedisSet(conn, test.RandomKey())
+	defer dest.Base().Delete()
+
+	keys := []redistypes.Type{l.Base(), dest.Base()}
+
+	t.Run("script not loaded", func(t *testing.T) {
+		_, _ = conn.Do("SCRIPT", "FLUSH")
+		_, _ = l.RightPush("abc", "def")
+
+		value, err := redis.String(s.Do(context.Background(), keys))
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", value)
+
+		card, _ := dest.Card()
+		assert.EqualValues(t, 1, card)
+	})
+
+	t.Run("script loaded", func(t *testing.T) {
+		value, err := redis.String(s.Do(context.Background(), keys))
+		assert.Nil(t, err)
+		assert.Equal(t, "def", value)
+
+		card, _ := dest.Card()
+		assert.EqualValues(t, 2, card)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		value, err := s.Do(context.Background(), keys)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("error in script", func(t *testing.T) {
+		_, _ = l.RightPush("abc")
+
+		bad := registry.Register("bad", `return redis.call("INCR", KEYS[1])`)
+		_, err := bad.Do(context.Background(), []redistypes.Type{l.Base()}, "unused")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestScript_Int64(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	s := registry.Register("add", `return tonumber(ARGV[1]) + tonumber(ARGV[2])`)
+
+	value, err := s.Int64(context.Background(), nil, 2, 3)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, value)
+}
+
+func TestScript_String(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	s := registry.Register("echo", `return ARGV[1]`)
+
+	value, err := s.String(context.Background(), nil, "abc")
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", value)
+
+	nilScript := registry.Register("nil", `return nil`)
+	_, err = nilScript.String(context.Background(), nil)
+	assert.Equal(t, redis.ErrNil, err)
+}
+
+func TestScript_Values(t *testing.T) {
+	registry := script.NewRegistry(redistypes.SingleConn(conn))
+	s := registry.Register("pair", `return {ARGV[1], tonumber(ARGV[2])}`)
+
+	values, err := s.Values(context.Background(), nil, "abc", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{[]byte("abc"), int64(5)}, values)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
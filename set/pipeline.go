@@ -0,0 +1,140 @@
+package set
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+)
+
+// MemberFuture is a Future for the reply to SRANDMEMBER without a count.
+type MemberFuture struct {
+	*redistypes.Future
+}
+
+// Result returns the member, or redistypes.ErrKeyNotFound if the set doesn't exist,
+// like Set.RandomMember.
+func (f MemberFuture) Result() (string, error) {
+	return randomMemberReply(f.Reply())
+}
+
+// MembersFuture is a Future for a reply holding members of a set.
+type MembersFuture struct {
+	*redistypes.Future
+}
+
+// Result returns the members. If the reply is nil, as for SPOP on a set that doesn't
+// exist, no members are returned, like Set.Pop.
+func (f MembersFuture) Result() ([]string, error) {
+	return popReply(f.Reply())
+}
+
+// MultiIsMemberFuture is a Future for the reply to SMISMEMBER.
+type MultiIsMemberFuture struct {
+	*redistypes.Future
+	n int
+}
+
+// Result returns whether each value is a member of the set, like Set.MultiIsMember.
+func (f MultiIsMemberFuture) Result() ([]bool, error) {
+	reply, err := f.Reply()
+	return multiIsMemberReply(reply, err, f.n)
+}
+
+// Pipelined is a Set whose commands are queued in a redistypes.Batch instead of being
+// sent immediately. Each method returns a future that is resolved when the Batch is
+// executed. The methods work like the Set methods with the same names. The set algebra
+// commands and Scan are not available.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Add queues the Redis command SADD. See Set.Add.
+	Add(values ...interface{}) redistypes.Uint64Future
+
+	// Card queues the Redis command SCARD. See Set.Card.
+	Card() redistypes.Uint64Future
+
+	// IsMember queues the Redis command SISMEMBER. See Set.IsMember.
+	IsMember(value interface{}) redistypes.BoolFuture
+
+	// Members queues the Redis command SMEMBERS. See Set.Members.
+	Members() MembersFuture
+
+	// Move queues the Redis command SMOVE. See Set.Move.
+	Move(destination Set, value interface{}) redistypes.BoolFuture
+
+	// MultiIsMember queues the Redis command SMISMEMBER. See Set.MultiIsMember.
+	MultiIsMember(values ...interface{}) MultiIsMemberFuture
+
+	// Pop queues the Redis command SPOP with a count. See Set.Pop.
+	Pop(count int64) MembersFuture
+
+	// RandomMember queues the Redis command SRANDMEMBER. See Set.RandomMember.
+	RandomMember() MemberFuture
+
+	// RandomMembers queues the Redis command SRANDMEMBER with a count. See
+	// Set.RandomMembers.
+	RandomMembers(count int64) MembersFuture
+
+	// Remove queues the Redis command SREM. See Set.Remove.
+	Remove(values ...interface{}) redistypes.Uint64Future
+}
+
+type pipelinedSet struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined set that queues the commands of s in b.
+func NewPipelined(b redistypes.Batch, s Set) Pipelined {
+	return &pipelinedSet{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, s.Base()),
+	}
+}
+
+func (r pipelinedSet) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedSet) Add(values ...interface{}) redistypes.Uint64Future {
+	values = internal.PrependInterface(r.base.Name(), values...)
+	return redistypes.Uint64Future{Future: r.batch.Queue("SADD", values...)}
+}
+
+func (r *pipelinedSet) Card() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("SCARD", r.base.Name())}
+}
+
+func (r *pipelinedSet) IsMember(value interface{}) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("SISMEMBER", r.base.Name(), value)}
+}
+
+func (r *pipelinedSet) Members() MembersFuture {
+	return MembersFuture{Future: r.batch.Queue("SMEMBERS", r.base.Name())}
+}
+
+func (r *pipelinedSet) Move(destination Set, value interface{}) redistypes.BoolFuture {
+	return redistypes.BoolFuture{Future: r.batch.Queue("SMOVE", r.base.Name(), destination.Base().Name(), value)}
+}
+
+func (r *pipelinedSet) MultiIsMember(values ...interface{}) MultiIsMemberFuture {
+	args := internal.PrependInterface(r.base.Name(), values...)
+	return MultiIsMemberFuture{Future: r.batch.Queue("SMISMEMBER", args...), n: len(values)}
+}
+
+func (r *pipelinedSet) Pop(count int64) MembersFuture {
+	return MembersFuture{Future: r.batch.Queue("SPOP", r.base.Name(), count)}
+}
+
+func (r *pipelinedSet) RandomMember() MemberFuture {
+	return MemberFuture{Future: r.batch.Queue("SRANDMEMBER", r.base.Name())}
+}
+
+func (r *pipelinedSet) RandomMembers(count int64) MembersFuture {
+	return MembersFuture{Future: r.batch.Queue("SRANDMEMBER", r.base.Name(), count)}
+}
+
+func (r *pipelinedSet) Remove(values ...interface{}) redistypes.Uint64Future {
+	values = internal.PrependInterface(r.base.Name(), values...)
+	return redistypes.Uint64Future{Future: r.batch.Queue("SREM", values...)}
+}
@@ -0,0 +1,109 @@
+package set_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	ps := set.NewPipelined(p, s)
+	assert.Equal(t, s.Base().Name(), ps.Base().Name())
+
+	add := ps.Add(1, 2, 3)
+	addExisting := ps.Add(3, 4)
+	card := ps.Card()
+	isMember := ps.IsMember(2)
+	remove := ps.Remove(1, 5)
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	value, err := add.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, value)
+
+	value, err = addExisting.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	value, err = card.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, value)
+
+	member, err := isMember.Result()
+	assert.Nil(t, err)
+	assert.True(t, member)
+
+	value, err = remove.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+}
+
+func TestPipelined_members(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	s2 := set.NewRedisSet(conn, test.RandomKey())
+	defer s2.Base().Delete()
+
+	missing := set.NewRedisSet(conn, test.RandomKey())
+
+	_, _ = s.Add("a", "b", "c")
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	ps := set.NewPipelined(p, s)
+	multiIsMember := ps.MultiIsMember("a", "z")
+	move := ps.Move(s2, "c")
+	members := ps.Members()
+	randomMember := ps.RandomMember()
+	randomMembers := ps.RandomMembers(-3)
+	pop := ps.Pop(5)
+	popMissing := set.NewPipelined(p, missing).Pop(1)
+	randomMissing := set.NewPipelined(p, missing).RandomMember()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	isMember, err := multiIsMember.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, isMember)
+
+	moved, err := move.Result()
+	assert.Nil(t, err)
+	assert.True(t, moved)
+
+	values, err := members.Result()
+	assert.Nil(t, err)
+	sort.Strings(values)
+	assert.Equal(t, []string{"a", "b"}, values)
+
+	value, err := randomMember.Result()
+	assert.Nil(t, err)
+	assert.Contains(t, []string{"a", "b"}, value)
+
+	values, err = randomMembers.Result()
+	assert.Nil(t, err)
+	assert.Len(t, values, 3)
+
+	values, err = pop.Result()
+	assert.Nil(t, err)
+	sort.Strings(values)
+	assert.Equal(t, []string{"a", "b"}, values)
+
+	values, err = popMissing.Result()
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	_, err = randomMissing.Result()
+	assert.Equal(t, redistypes.ErrKeyNotFound, err)
+}
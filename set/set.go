@@ -1,6 +1,9 @@
 package set
 
 import (
+	"context"
+	"errors"
+
 	"github.com/MasterOfBinary/redistypes"
 	"github.com/MasterOfBinary/redistypes/internal"
 	"github.com/garyburd/redigo/redis"
@@ -23,19 +26,153 @@ type Set interface {
 	//
 	// See https://redis.io/commands/scard.
 	Card() (uint64, error)
+
+	// CloneTo copies the set to a new key called name using the Redis command COPY,
+	// and returns a Set for the copy. If name already exists, it is overwritten. If
+	// the set does not exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (Set, error)
+
+	// Diff implements the Redis command SDIFF. It returns the members of the set that
+	// aren't members of any of others.
+	//
+	// See https://redis.io/commands/sdiff.
+	Diff(others ...Set) ([]string, error)
+
+	// DiffStore implements the Redis command SDIFFSTORE. It stores the difference
+	// between the set and others in a new set with the given name, and returns it. If
+	// name already exists, it is overwritten.
+	//
+	// See https://redis.io/commands/sdiffstore.
+	DiffStore(name string, others ...Set) (Set, error)
+
+	// Inter implements the Redis command SINTER. It returns the members that are in the
+	// set and all of others.
+	//
+	// See https://redis.io/commands/sinter.
+	Inter(others ...Set) ([]string, error)
+
+	// InterCard implements the Redis command SINTERCARD. It returns the number of
+	// members in the intersection of the set and others, without returning the members
+	// themselves. If limit is greater than 0, Redis stops counting once it reaches
+	// limit. It requires Redis 7.0 or later.
+	//
+	// See https://redis.io/commands/sintercard.
+	InterCard(limit uint64, others ...Set) (uint64, error)
+
+	// InterStore implements the Redis command SINTERSTORE. It stores the intersection
+	// of the set and others in a new set with the given name, and returns it. If name
+	// already exists, it is overwritten.
+	//
+	// See https://redis.io/commands/sinterstore.
+	InterStore(name string, others ...Set) (Set, error)
+
+	// IsMember implements the Redis command SISMEMBER. It returns whether value is a
+	// member of the set.
+	//
+	// See https://redis.io/commands/sismember.
+	IsMember(value interface{}) (bool, error)
+
+	// Members implements the Redis command SMEMBERS. It returns all of the members of
+	// the set, in no particular order. It blocks the server while it runs, so Scan
+	// should be used for large sets.
+	//
+	// See https://redis.io/commands/smembers.
+	Members() ([]string, error)
+
+	// Move implements the Redis command SMOVE. It moves value from the set to
+	// destination, and returns whether it was a member of the set. If it was, it is
+	// always removed from the set, even if it was already a member of destination.
+	//
+	// See https://redis.io/commands/smove.
+	Move(destination Set, value interface{}) (bool, error)
+
+	// MultiIsMember implements the Redis command SMISMEMBER. It returns whether each of
+	// values is a member of the set, in the same order.
+	//
+	// See https://redis.io/commands/smismember.
+	MultiIsMember(values ...interface{}) ([]bool, error)
+
+	// Pop implements the Redis command SPOP with a count. It removes up to count random
+	// members from the set and returns them. If the set doesn't exist, no members are
+	// returned.
+	//
+	// See https://redis.io/commands/spop.
+	Pop(count int64) ([]string, error)
+
+	// RandomMember implements the Redis command SRANDMEMBER. It returns a random member
+	// of the set without removing it. If the set doesn't exist,
+	// redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/srandmember.
+	RandomMember() (string, error)
+
+	// RandomMembers implements the Redis command SRANDMEMBER with a count. If count is
+	// positive, it returns up to count distinct members. If count is negative, it
+	// returns -count members, which may include the same member more than once.
+	//
+	// See https://redis.io/commands/srandmember.
+	RandomMembers(count int64) ([]string, error)
+
+	// Remove implements the Redis command SREM. It removes values from the set and
+	// returns the number removed, not including the ones that weren't members.
+	//
+	// See https://redis.io/commands/srem.
+	Remove(values ...interface{}) (uint64, error)
+
+	// Scan implements the Redis command SSCAN. It pages through the set a few members
+	// at a time, so unlike Members it doesn't block the server on large sets. match is
+	// a glob-style pattern that the members must match, or empty to return all of them.
+	// count is how many members Redis should look at for each page, or 0 for its
+	// default.
+	//
+	// SSCAN can return the same member on more than one page, and the Scanner returns it
+	// each time. Call Unique on the Scanner to skip the members it has already returned.
+	// Members added or removed while scanning may or may not be returned.
+	//
+	// See https://redis.io/commands/sscan.
+	Scan(match string, count int64) *Scanner
+
+	// Union implements the Redis command SUNION. It returns the members that are in the
+	// set or any of others.
+	//
+	// See https://redis.io/commands/sunion.
+	Union(others ...Set) ([]string, error)
+
+	// UnionStore implements the Redis command SUNIONSTORE. It stores the union of the
+	// set and others in a new set with the given name, and returns it. If name already
+	// exists, it is overwritten.
+	//
+	// See https://redis.io/commands/sunionstore.
+	UnionStore(name string, others ...Set) (Set, error)
+
+	// WithContext returns a copy of the Set that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) Set
 }
 
 type redisSet struct {
-	conn redis.Conn
-	base redistypes.Type
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
 }
 
 // NewRedisSet creates a Redis implementation of Set given redigo connection conn and name. The
 // Redis key used to identify the Set will be name.
 func NewRedisSet(conn redis.Conn, name string) Set {
+	return NewRedisSetFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisSetFromProvider creates a Redis implementation of Set given ConnProvider p and name.
+// Each command borrows a connection from p. The Redis key used to identify the Set
+// will be name.
+func NewRedisSetFromProvider(p redistypes.ConnProvider, name string) Set {
 	return &redisSet{
-		conn: conn,
-		base: redistypes.NewRedisType(conn, name),
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
 	}
 }
 
@@ -45,9 +182,227 @@ func (r redisSet) Base() redistypes.Type {
 
 func (r *redisSet) Add(values ...interface{}) (uint64, error) {
 	values = internal.PrependInterface(r.Base().Name(), values...)
-	return redis.Uint64(r.conn.Do("SADD", values...))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "SADD", values...))
 }
 
 func (r *redisSet) Card() (uint64, error) {
-	return redis.Uint64(r.conn.Do("SCARD", r.Base().Name()))
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "SCARD", r.Base().Name()))
+}
+
+func (r *redisSet) CloneTo(name string) (Set, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisSetFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisSet) Diff(others ...Set) ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "SDIFF", r.keys(others)...))
+}
+
+func (r *redisSet) DiffStore(name string, others ...Set) (Set, error) {
+	return r.store("SDIFFSTORE", name, others)
+}
+
+func (r *redisSet) Inter(others ...Set) ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "SINTER", r.keys(others)...))
+}
+
+func (r *redisSet) InterCard(limit uint64, others ...Set) (uint64, error) {
+	args := append([]interface{}{1 + len(others)}, r.keys(others)...)
+	if limit > 0 {
+		args = append(args, "LIMIT", limit)
+	}
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "SINTERCARD", args...))
+}
+
+func (r *redisSet) InterStore(name string, others ...Set) (Set, error) {
+	return r.store("SINTERSTORE", name, others)
+}
+
+func (r *redisSet) IsMember(value interface{}) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "SISMEMBER", r.Base().Name(), value))
+}
+
+func (r *redisSet) Members() ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "SMEMBERS", r.Base().Name()))
+}
+
+func (r *redisSet) Move(destination Set, value interface{}) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "SMOVE", r.Base().Name(), destination.Base().Name(), value))
+}
+
+func (r *redisSet) MultiIsMember(values ...interface{}) ([]bool, error) {
+	args := internal.PrependInterface(r.Base().Name(), values...)
+	reply, err := internal.Do(r.ctx, r.provider, "SMISMEMBER", args...)
+	return multiIsMemberReply(reply, err, len(values))
+}
+
+func (r *redisSet) Pop(count int64) ([]string, error) {
+	return popReply(internal.Do(r.ctx, r.provider, "SPOP", r.Base().Name(), count))
+}
+
+func (r *redisSet) RandomMember() (string, error) {
+	return randomMemberReply(internal.Do(r.ctx, r.provider, "SRANDMEMBER", r.Base().Name()))
+}
+
+func (r *redisSet) RandomMembers(count int64) ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "SRANDMEMBER", r.Base().Name(), count))
+}
+
+func (r *redisSet) Remove(values ...interface{}) (uint64, error) {
+	values = internal.PrependInterface(r.Base().Name(), values...)
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "SREM", values...))
+}
+
+func (r *redisSet) Scan(match string, count int64) *Scanner {
+	return &Scanner{
+		scanner: internal.NewScanner(r.ctx, r.provider, "SSCAN", r.Base().Name(), match, count, 1),
+	}
+}
+
+func (r *redisSet) Union(others ...Set) ([]string, error) {
+	return redis.Strings(internal.Do(r.ctx, r.provider, "SUNION", r.keys(others)...))
+}
+
+func (r *redisSet) UnionStore(name string, others ...Set) (Set, error) {
+	return r.store("SUNIONSTORE", name, others)
+}
+
+func (r *redisSet) WithContext(ctx context.Context) Set {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisSet{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// Scanner returns the members of a set one at a time, fetching them from Redis in
+// pages with SSCAN. Loop while Next returns true, then check Err:
+//
+//	sc := s.Scan("user:*", 100)
+//	for sc.Next() {
+//		fmt.Println(sc.Member())
+//	}
+//	if err := sc.Err(); err != nil {
+//		// handle the error
+//	}
+//
+// A member that SSCAN returns more than once is returned more than once, unless Unique
+// is called. Next returns false as soon as the context of the Set is done, and Err
+// returns ctx.Err().
+type Scanner struct {
+	scanner *internal.Scanner
+	seen    map[string]bool
+	member  string
+	err     error
+}
+
+// Unique makes the Scanner skip the members it has already returned, so each member is
+// returned once. To do that, it keeps every member it returns in memory until it is
+// done, which on a large set costs as much client memory as Members. It must be called
+// before the first call to Next, and returns s.
+func (s *Scanner) Unique() *Scanner {
+	s.seen = make(map[string]bool)
+	return s
+}
+
+// Next moves to the next member. It returns false when the whole set has been scanned,
+// a command fails or the context is done.
+func (s *Scanner) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	for s.scanner.Next() {
+		member, err := redis.String(s.scanner.Item()[0], nil)
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if s.seen == nil {
+			s.member = member
+			return true
+		} else if !s.seen[member] {
+			s.seen[member] = true
+			s.member = member
+			return true
+		}
+	}
+	return false
+}
+
+// Member returns the member that Next moved to.
+func (s *Scanner) Member() string {
+	return s.member
+}
+
+// Err returns the error that stopped the Scanner, if any.
+func (s *Scanner) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.scanner.Err()
+}
+
+// multiIsMemberReply converts the reply of SMISMEMBER for n values to a bool for each.
+func multiIsMemberReply(reply interface{}, err error, n int) ([]bool, error) {
+	replies, err := redis.Ints(reply, err)
+	if err != nil {
+		return nil, err
+	} else if len(replies) != n {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	members := make([]bool, len(replies))
+	for i, reply := range replies {
+		members[i] = reply == 1
+	}
+	return members, nil
+}
+
+// popReply converts the reply of SPOP with a count to the members that were popped,
+// which are nil if the set doesn't exist.
+func popReply(reply interface{}, err error) ([]string, error) {
+	members, err := redis.Strings(reply, err)
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	return members, err
+}
+
+// randomMemberReply converts the reply of SRANDMEMBER without a count to the member,
+// returning redistypes.ErrKeyNotFound if the set doesn't exist.
+func randomMemberReply(reply interface{}, err error) (string, error) {
+	member, err := redis.String(reply, err)
+	if err == redis.ErrNil {
+		return "", redistypes.ErrKeyNotFound
+	}
+	return member, err
+}
+
+// keys returns the names of the set and others, in that order.
+func (r *redisSet) keys(others []Set) []interface{} {
+	keys := []interface{}{r.Base().Name()}
+	for _, other := range others {
+		keys = append(keys, other.Base().Name())
+	}
+	return keys
+}
+
+func (r *redisSet) store(cmd, name string, others []Set) (Set, error) {
+	args := append([]interface{}{name}, r.keys(others)...)
+	if _, err := internal.Do(r.ctx, r.provider, cmd, args...); err != nil {
+		return nil, err
+	}
+
+	return NewRedisSetFromProvider(r.provider, name).WithContext(r.ctx), nil
 }
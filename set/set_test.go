@@ -1,12 +1,16 @@
 package set_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/MasterOfBinary/redistypes"
 	"github.com/MasterOfBinary/redistypes/internal"
 	"github.com/MasterOfBinary/redistypes/internal/test"
 	"github.com/MasterOfBinary/redistypes/set"
@@ -16,6 +20,56 @@ import (
 
 var conn redis.Conn
 
+// members returns the sorted members of s.
+func members(t *testing.T, s set.Set) []string {
+	values, err := s.Members()
+	assert.Nil(t, err)
+	sort.Strings(values)
+	return values
+}
+
+// algebraSets returns three sets with overlapping members for testing set algebra.
+func algebraSets() (set.Set, set.Set, set.Set) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	_, _ = s.Add("a", "b", "c", "d")
+
+	other1 := set.NewRedisSet(conn, test.RandomKey())
+	_, _ = other1.Add("b", "c", "e")
+
+	other2 := set.NewRedisSet(conn, test.RandomKey())
+	_, _ = other2.Add("c", "d", "f")
+
+	return s, other1, other2
+}
+
+func TestNewRedisSetFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	s := set.NewRedisSetFromProvider(pool, test.RandomKey())
+	defer s.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.Add(i)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	value, err := s.Card()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
 func TestRedisSet_Add(t *testing.T) {
 	s := set.NewRedisSet(conn, test.RandomKey())
 	defer s.Base().Delete()
@@ -62,6 +116,513 @@ func TestRedisSet_Card(t *testing.T) {
 	})
 }
 
+func TestRedisSet_CloneTo(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := s.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Add(1, 2, 3)
+
+		clone, err := s.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = s.Add(4)
+
+		value, err := clone.Card()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, value)
+	})
+}
+
+func TestRedisSet_Diff(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	t.Run("no others", func(t *testing.T) {
+		value, err := s.Diff()
+		assert.Nil(t, err)
+		sort.Strings(value)
+		assert.Equal(t, []string{"a", "b", "c", "d"}, value)
+	})
+
+	t.Run("several others", func(t *testing.T) {
+		value, err := s.Diff(other1, other2)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a"}, value)
+	})
+
+	t.Run("non-existing other", func(t *testing.T) {
+		value, err := s.Diff(set.NewRedisSet(conn, test.RandomKey()))
+		assert.Nil(t, err)
+		assert.Len(t, value, 4)
+	})
+}
+
+func TestRedisSet_DiffStore(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	diff, err := s.DiffStore(test.RandomKey(), other1)
+	assert.Nil(t, err)
+	defer diff.Base().Delete()
+
+	assert.Equal(t, []string{"a", "d"}, members(t, diff))
+}
+
+func TestRedisSet_Inter(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	t.Run("one other", func(t *testing.T) {
+		value, err := s.Inter(other1)
+		assert.Nil(t, err)
+		sort.Strings(value)
+		assert.Equal(t, []string{"b", "c"}, value)
+	})
+
+	t.Run("several others", func(t *testing.T) {
+		value, err := s.Inter(other1, other2)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"c"}, value)
+	})
+
+	t.Run("non-existing other", func(t *testing.T) {
+		value, err := s.Inter(other1, set.NewRedisSet(conn, test.RandomKey()))
+		assert.Nil(t, err)
+		assert.Empty(t, value)
+	})
+}
+
+func TestRedisSet_InterCard(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	t.Run("no limit", func(t *testing.T) {
+		value, err := s.InterCard(0, other1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		value, err := s.InterCard(1, other1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+
+	t.Run("several others", func(t *testing.T) {
+		value, err := s.InterCard(0, other1, other2)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+}
+
+func TestRedisSet_InterStore(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	t.Run("new key", func(t *testing.T) {
+		inter, err := s.InterStore(test.RandomKey(), other2)
+		assert.Nil(t, err)
+		defer inter.Base().Delete()
+
+		assert.Equal(t, []string{"c", "d"}, members(t, inter))
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		inter := set.NewRedisSet(conn, test.RandomKey())
+		defer inter.Base().Delete()
+		_, _ = inter.Add("x", "y")
+
+		value, err := s.InterStore(inter.Base().Name(), other1, other2)
+		assert.Nil(t, err)
+		assert.Equal(t, inter.Base().Name(), value.Base().Name())
+
+		assert.Equal(t, []string{"c"}, members(t, inter))
+	})
+}
+
+func TestRedisSet_IsMember(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.IsMember(1)
+		assert.Nil(t, err)
+		assert.False(t, value)
+	})
+
+	t.Run("non-existing value", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		value, err := s.IsMember(4)
+		assert.Nil(t, err)
+		assert.False(t, value)
+	})
+
+	t.Run("existing value", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		value, err := s.IsMember(2)
+		assert.Nil(t, err)
+		assert.True(t, value)
+	})
+}
+
+func TestRedisSet_Members(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.Members()
+		assert.Nil(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add("a", "b", "c")
+		value, err := s.Members()
+		assert.Nil(t, err)
+		sort.Strings(value)
+		assert.Equal(t, []string{"a", "b", "c"}, value)
+	})
+}
+
+func TestRedisSet_Move(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	dest := set.NewRedisSet(conn, test.RandomKey())
+	defer dest.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.Move(dest, 1)
+		assert.Nil(t, err)
+		assert.False(t, value)
+	})
+
+	t.Run("non-existing value", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = dest.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		value, err := s.Move(dest, 4)
+		assert.Nil(t, err)
+		assert.False(t, value)
+
+		card, _ := dest.Card()
+		assert.EqualValues(t, 0, card)
+	})
+
+	t.Run("existing value", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = dest.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		value, err := s.Move(dest, 2)
+		assert.Nil(t, err)
+		assert.True(t, value)
+
+		member, _ := s.IsMember(2)
+		assert.False(t, member)
+		member, _ = dest.IsMember(2)
+		assert.True(t, member)
+	})
+
+	t.Run("value in both sets", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = dest.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		_, _ = dest.Add(2)
+		value, err := s.Move(dest, 2)
+		assert.Nil(t, err)
+		assert.True(t, value)
+
+		card, _ := s.Card()
+		assert.EqualValues(t, 2, card)
+		card, _ = dest.Card()
+		assert.EqualValues(t, 1, card)
+	})
+}
+
+func TestRedisSet_MultiIsMember(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.MultiIsMember(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []bool{false, false}, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		value, err := s.MultiIsMember(3, 4, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []bool{true, false, true}, value)
+	})
+}
+
+func TestRedisSet_Pop(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.Pop(2)
+		assert.Nil(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("count less than size", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add("a", "b", "c")
+		value, err := s.Pop(2)
+		assert.Nil(t, err)
+		assert.Len(t, value, 2)
+
+		members, _ := s.Members()
+		assert.Len(t, members, 1)
+		assert.NotContains(t, value, members[0])
+	})
+
+	t.Run("count greater than size", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add("a", "b", "c")
+		value, err := s.Pop(5)
+		assert.Nil(t, err)
+		sort.Strings(value)
+		assert.Equal(t, []string{"a", "b", "c"}, value)
+
+		exists, _ := s.Base().Exists()
+		assert.False(t, exists)
+	})
+}
+
+func TestRedisSet_RandomMember(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := s.RandomMember()
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add("a", "b", "c")
+		value, err := s.RandomMember()
+		assert.Nil(t, err)
+		assert.Contains(t, []string{"a", "b", "c"}, value)
+
+		card, _ := s.Card()
+		assert.EqualValues(t, 3, card)
+	})
+}
+
+func TestRedisSet_RandomMembers(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.RandomMembers(2)
+		assert.Nil(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("positive count", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add("a", "b", "c")
+		value, err := s.RandomMembers(5)
+		assert.Nil(t, err)
+		sort.Strings(value)
+		assert.Equal(t, []string{"a", "b", "c"}, value)
+	})
+
+	t.Run("negative count", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add("a")
+		value, err := s.RandomMembers(-3)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "a", "a"}, value)
+	})
+}
+
+func TestRedisSet_Remove(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.Remove(1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add(1, 2, 3)
+		value, err := s.Remove(1, 3, 4)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+
+		members, _ := s.Members()
+		assert.Equal(t, []string{"2"}, members)
+	})
+}
+
+// scanPages is a redis.Conn that replies to each command with the next of replies.
+// It is used to test how Scan handles pages that Redis doesn't return reliably.
+type scanPages struct {
+	replies []interface{}
+}
+
+func (c *scanPages) Close() error                                       { return nil }
+func (c *scanPages) Err() error                                         { return nil }
+func (c *scanPages) Send(commandName string, args ...interface{}) error { return nil }
+func (c *scanPages) Flush() error                                       { return nil }
+func (c *scanPages) Receive() (interface{}, error)                      { return nil, nil }
+
+func (c *scanPages) Do(commandName string, args ...interface{}) (interface{}, error) {
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
+
+// duplicatePages returns a scanPages with SSCAN pages that repeat some members.
+func duplicatePages() *scanPages {
+	return &scanPages{replies: []interface{}{
+		[]interface{}{[]byte("5"), []interface{}{[]byte("a"), []byte("b")}},
+		[]interface{}{[]byte("9"), []interface{}{[]byte("b"), []byte("c"), []byte("a")}},
+		[]interface{}{[]byte("0"), []interface{}{[]byte("d"), []byte("c")}},
+	}}
+}
+
+func TestRedisSet_Scan(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	values := make([]interface{}, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("member%d", i))
+	}
+	_, _ = s.Add(values...)
+
+	t.Run("all members", func(t *testing.T) {
+		var members []string
+		sc := s.Scan("", 10)
+		for sc.Next() {
+			members = append(members, sc.Member())
+		}
+		assert.Nil(t, sc.Err())
+		assert.Len(t, members, 100)
+		assert.Contains(t, members, "member42")
+	})
+
+	t.Run("match", func(t *testing.T) {
+		var members []string
+		sc := s.Scan("member1?", 0)
+		for sc.Next() {
+			members = append(members, sc.Member())
+		}
+		assert.Nil(t, sc.Err())
+		sort.Strings(members)
+		assert.Equal(t, []string{"member10", "member11", "member12", "member13", "member14",
+			"member15", "member16", "member17", "member18", "member19"}, members)
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		var members []string
+		sc := set.NewRedisSet(duplicatePages(), test.RandomKey()).Scan("", 0)
+		for sc.Next() {
+			members = append(members, sc.Member())
+		}
+		assert.Nil(t, sc.Err())
+		assert.Equal(t, []string{"a", "b", "b", "c", "a", "d", "c"}, members)
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		var members []string
+		sc := set.NewRedisSet(duplicatePages(), test.RandomKey()).Scan("", 0).Unique()
+		for sc.Next() {
+			members = append(members, sc.Member())
+		}
+		assert.Nil(t, sc.Err())
+		assert.Equal(t, []string{"a", "b", "c", "d"}, members)
+	})
+
+	t.Run("non-existing key", func(t *testing.T) {
+		sc := set.NewRedisSet(conn, test.RandomKey()).Scan("", 0)
+		assert.False(t, sc.Next())
+		assert.Nil(t, sc.Err())
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		sc := s.WithContext(ctx).Scan("", 0)
+		assert.False(t, sc.Next())
+		assert.Equal(t, context.Canceled, sc.Err())
+	})
+}
+
+func TestRedisSet_Union(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	value, err := s.Union(other1, other2)
+	assert.Nil(t, err)
+	sort.Strings(value)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, value)
+}
+
+func TestRedisSet_UnionStore(t *testing.T) {
+	s, other1, other2 := algebraSets()
+	defer s.Base().Delete()
+	defer other1.Base().Delete()
+	defer other2.Base().Delete()
+
+	union, err := s.UnionStore(test.RandomKey(), other1)
+	assert.Nil(t, err)
+	defer union.Base().Delete()
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, members(t, union))
+}
+
+func TestRedisSet_WithContext(t *testing.T) {
+	s := set.NewRedisSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.WithContext(ctx).Add(1)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = s.WithContext(ctx).Base().Exists()
+	assert.Equal(t, context.Canceled, err)
+
+	value, err := s.Card()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+}
+
 func TestMain(m *testing.M) {
 	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
 	if err != nil {
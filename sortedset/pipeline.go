@@ -0,0 +1,74 @@
+package sortedset
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+)
+
+// Pipelined is a SortedSet whose commands are queued in a redistypes.Batch instead of
+// being sent immediately. Each method returns a future that is resolved when the Batch
+// is executed. The methods work like the SortedSet methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Add queues the Redis command ZADD. See SortedSet.Add.
+	Add(members []Member, options ...AddOption) redistypes.Uint64Future
+
+	// Card queues the Redis command ZCARD. See SortedSet.Card.
+	Card() redistypes.Uint64Future
+
+	// IncrBy queues the Redis command ZINCRBY. See SortedSet.IncrBy.
+	IncrBy(member string, increment float64) redistypes.Float64Future
+
+	// Range queues the Redis command ZRANGE. See SortedSet.Range.
+	Range(start, stop interface{}, options RangeOptions) redistypes.ValuesFuture
+
+	// Remove queues the Redis command ZREM. See SortedSet.Remove.
+	Remove(members ...string) redistypes.Uint64Future
+
+	// Score queues the Redis command ZSCORE. See SortedSet.Score. If the member
+	// doesn't exist, the future returns redis.ErrNil.
+	Score(member string) redistypes.Float64Future
+}
+
+type pipelinedSortedSet struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined sorted set that queues the commands of s in b.
+func NewPipelined(b redistypes.Batch, s SortedSet) Pipelined {
+	return &pipelinedSortedSet{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, s.Base()),
+	}
+}
+
+func (r pipelinedSortedSet) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedSortedSet) Add(members []Member, options ...AddOption) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("ZADD", addArgs(r.base.Name(), members, options)...)}
+}
+
+func (r *pipelinedSortedSet) Card() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("ZCARD", r.base.Name())}
+}
+
+func (r *pipelinedSortedSet) IncrBy(member string, increment float64) redistypes.Float64Future {
+	return redistypes.Float64Future{Future: r.batch.Queue("ZINCRBY", r.base.Name(), increment, member)}
+}
+
+func (r *pipelinedSortedSet) Range(start, stop interface{}, options RangeOptions) redistypes.ValuesFuture {
+	return redistypes.ValuesFuture{Future: r.batch.Queue("ZRANGE", rangeArgs(r.base.Name(), start, stop, options)...)}
+}
+
+func (r *pipelinedSortedSet) Remove(members ...string) redistypes.Uint64Future {
+	args := memberArgs(r.base.Name(), members)
+	return redistypes.Uint64Future{Future: r.batch.Queue("ZREM", args...)}
+}
+
+func (r *pipelinedSortedSet) Score(member string) redistypes.Float64Future {
+	return redistypes.Float64Future{Future: r.batch.Queue("ZSCORE", r.base.Name(), member)}
+}
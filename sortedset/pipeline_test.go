@@ -0,0 +1,59 @@
+package sortedset_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/sortedset"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	ps := sortedset.NewPipelined(p, s)
+	assert.Equal(t, s.Base().Name(), ps.Base().Name())
+
+	add := ps.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+	incr := ps.IncrBy("a", 2.5)
+	remove := ps.Remove("b")
+	score := ps.Score("a")
+	missing := ps.Score("b")
+	card := ps.Card()
+	values := ps.Range(0, -1, sortedset.RangeOptions{})
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	count, err := add.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, count)
+
+	value, err := incr.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, 3.5, value)
+
+	count, err = remove.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	value, err = score.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, 3.5, value)
+
+	_, err = missing.Result()
+	assert.Equal(t, redis.ErrNil, err)
+
+	count, err = card.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	strs, err := redis.Strings(values.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, strs)
+}
@@ -0,0 +1,548 @@
+// Package sortedset contains a Go implementation of the sorted set data structure in Redis. For more
+// information about how the data structure works, see the Redis documentation.
+package sortedset
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrMemberNotFound is returned when a member that doesn't exist in a sorted set is
+// looked up.
+var ErrMemberNotFound = errors.New("Member does not exist")
+
+// Member is a member of a sorted set with its score.
+type Member struct {
+	Value string
+	Score float64
+}
+
+// AddOption is an option for the Redis command ZADD.
+type AddOption string
+
+const (
+	// AddNX only adds new members, and doesn't update the ones that already exist.
+	AddNX AddOption = "NX"
+
+	// AddXX only updates members that already exist, and doesn't add new ones.
+	AddXX AddOption = "XX"
+
+	// AddGT only updates members whose new score is greater than their current score.
+	// New members are still added.
+	AddGT AddOption = "GT"
+
+	// AddLT only updates members whose new score is less than their current score.
+	// New members are still added.
+	AddLT AddOption = "LT"
+
+	// AddCH makes Add return the number of members that were added or whose score
+	// changed, instead of only the number added.
+	AddCH AddOption = "CH"
+)
+
+// RangeBy is the kind of range used by the Redis command ZRANGE.
+type RangeBy string
+
+const (
+	// ByRank selects members by their index in the sorted set. It is the default.
+	ByRank RangeBy = ""
+
+	// ByScore selects members by their score, as with ZRANGEBYSCORE.
+	ByScore RangeBy = "BYSCORE"
+
+	// ByLex selects members by lexicographical order, as with ZRANGEBYLEX. It should
+	// only be used when all of the members have the same score.
+	ByLex RangeBy = "BYLEX"
+)
+
+// RangeOptions are the options for the Redis command ZRANGE.
+type RangeOptions struct {
+	// By is the kind of range. The start and stop arguments of Range are indices for
+	// ByRank, scores such as 1.5, "(1.5" or "-inf" for ByScore, and strings such as
+	// "[a", "(a" or "-" for ByLex.
+	By RangeBy
+
+	// Reverse returns the members from the highest score to the lowest. With ByScore
+	// and ByLex, start is then the higher end of the range.
+	Reverse bool
+
+	// Offset and Count limit the members returned by a ByScore or ByLex range. If
+	// Count is 0, there is no limit. A negative Count returns all of the members
+	// after Offset.
+	Offset int64
+	Count  int64
+}
+
+// Aggregate is the way the Redis commands ZUNIONSTORE and ZINTERSTORE combine the
+// scores of a member that exists in several sorted sets.
+type Aggregate string
+
+const (
+	// AggregateSum adds the scores. It is the default.
+	AggregateSum Aggregate = "SUM"
+
+	// AggregateMin uses the lowest score.
+	AggregateMin Aggregate = "MIN"
+
+	// AggregateMax uses the highest score.
+	AggregateMax Aggregate = "MAX"
+)
+
+// StoreOptions are the options for the Redis commands ZUNIONSTORE and ZINTERSTORE.
+type StoreOptions struct {
+	// Weights are multiplied with the scores of each sorted set, starting with the
+	// one whose method is called and followed by the others. If Weights is empty,
+	// each weight is 1.
+	Weights []float64
+
+	// Aggregate is the way the scores of a member are combined. If it is empty,
+	// AggregateSum is used.
+	Aggregate Aggregate
+}
+
+// SortedSet is a Redis implementation of a sorted set.
+type SortedSet interface {
+	// Base returns the base Type.
+	Base() redistypes.Type
+
+	// Add implements the Redis command ZADD. It adds members to the sorted set, or
+	// updates their scores if they already exist, and returns the number of members
+	// added. The behaviour can be changed with options, as described in the Redis
+	// documentation.
+	//
+	// See https://redis.io/commands/zadd.
+	Add(members []Member, options ...AddOption) (uint64, error)
+
+	// AddIncr implements the Redis command ZADD with the INCR option. It increments
+	// the score of member by increment like IncrBy, and returns the new score. If
+	// options prevent the score from being updated, false is returned.
+	//
+	// See https://redis.io/commands/zadd.
+	AddIncr(member string, increment float64, options ...AddOption) (float64, bool, error)
+
+	// BlockingPopMax implements the Redis command BZPOPMAX. It works like PopMax with
+	// a count of 1, but it blocks until a member exists in the sorted set or timeout
+	// is reached. If the timeout is reached, redis.ErrNil is returned. A timeout of 0
+	// can be used to block indefinitely.
+	//
+	// Since the timeout is sent in seconds, millisecond-level precision is not
+	// possible. If the timeout is not a multiple of one second, an error will be
+	// returned.
+	//
+	// See https://redis.io/commands/bzpopmax.
+	BlockingPopMax(timeout time.Duration) (Member, error)
+
+	// BlockingPopMin implements the Redis command BZPOPMIN. It works like
+	// BlockingPopMax, but it pops the member with the lowest score.
+	//
+	// See https://redis.io/commands/bzpopmin.
+	BlockingPopMin(timeout time.Duration) (Member, error)
+
+	// Card implements the Redis command ZCARD. It returns the number of members in
+	// the sorted set, or 0 if it doesn't exist.
+	//
+	// See https://redis.io/commands/zcard.
+	Card() (uint64, error)
+
+	// CloneTo copies the sorted set to a new key called name using the Redis command
+	// COPY, and returns a SortedSet for the copy. If name already exists, it is
+	// overwritten. If the sorted set does not exist, redistypes.ErrKeyNotFound is
+	// returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (SortedSet, error)
+
+	// IncrBy implements the Redis command ZINCRBY. It increments the score of member
+	// by increment and returns the new score. If the member doesn't exist, it is
+	// added with increment as its score.
+	//
+	// See https://redis.io/commands/zincrby.
+	IncrBy(member string, increment float64) (float64, error)
+
+	// InterStore implements the Redis command ZINTERSTORE. It stores the intersection
+	// of the sorted set and others in a new sorted set with the given name, and
+	// returns it. If name already exists, it is overwritten.
+	//
+	// See https://redis.io/commands/zinterstore.
+	InterStore(name string, options StoreOptions, others ...SortedSet) (SortedSet, error)
+
+	// MultiScore implements the Redis command ZMSCORE. It returns the scores of
+	// members. Members that don't exist are left out of the map.
+	//
+	// See https://redis.io/commands/zmscore.
+	MultiScore(members ...string) (map[string]float64, error)
+
+	// PopMax implements the Redis command ZPOPMAX. It removes up to count members
+	// with the highest scores and returns them, starting with the highest.
+	//
+	// See https://redis.io/commands/zpopmax.
+	PopMax(count int64) ([]Member, error)
+
+	// PopMin implements the Redis command ZPOPMIN. It removes up to count members
+	// with the lowest scores and returns them, starting with the lowest.
+	//
+	// See https://redis.io/commands/zpopmin.
+	PopMin(count int64) ([]Member, error)
+
+	// Range implements the Redis command ZRANGE. It returns the members between start
+	// and stop, which are interpreted according to options.
+	//
+	// See https://redis.io/commands/zrange.
+	Range(start, stop interface{}, options RangeOptions) ([]string, error)
+
+	// RangeWithScores implements the Redis command ZRANGE with the WITHSCORES option.
+	// It works like Range, but it returns the members with their scores. It can't be
+	// used with ByLex.
+	//
+	// See https://redis.io/commands/zrange.
+	RangeWithScores(start, stop interface{}, options RangeOptions) ([]Member, error)
+
+	// Rank implements the Redis command ZRANK. It returns the 0-based index of member
+	// when the sorted set is ordered from the lowest score to the highest. If the
+	// member doesn't exist, ErrMemberNotFound is returned.
+	//
+	// See https://redis.io/commands/zrank.
+	Rank(member string) (uint64, error)
+
+	// Remove implements the Redis command ZREM. It removes members from the sorted
+	// set and returns the number removed, not including the ones that didn't exist.
+	//
+	// See https://redis.io/commands/zrem.
+	Remove(members ...string) (uint64, error)
+
+	// RemoveRangeByLex implements the Redis command ZREMRANGEBYLEX. It removes the
+	// members between min and max in lexicographical order, such as "[a" or "(a", and
+	// returns the number removed.
+	//
+	// See https://redis.io/commands/zremrangebylex.
+	RemoveRangeByLex(min, max string) (uint64, error)
+
+	// RemoveRangeByRank implements the Redis command ZREMRANGEBYRANK. It removes the
+	// members from index start to stop and returns the number removed. Negative
+	// indices start at the member with the highest score.
+	//
+	// See https://redis.io/commands/zremrangebyrank.
+	RemoveRangeByRank(start, stop int64) (uint64, error)
+
+	// RemoveRangeByScore implements the Redis command ZREMRANGEBYSCORE. It removes the
+	// members with scores between min and max, such as 1.5, "(1.5" or "-inf", and
+	// returns the number removed.
+	//
+	// See https://redis.io/commands/zremrangebyscore.
+	RemoveRangeByScore(min, max interface{}) (uint64, error)
+
+	// RevRank implements the Redis command ZREVRANK. It works like Rank, but the sorted
+	// set is ordered from the highest score to the lowest.
+	//
+	// See https://redis.io/commands/zrevrank.
+	RevRank(member string) (uint64, error)
+
+	// Score implements the Redis command ZSCORE. It returns the score of member. If the
+	// member doesn't exist, ErrMemberNotFound is returned.
+	//
+	// See https://redis.io/commands/zscore.
+	Score(member string) (float64, error)
+
+	// UnionStore implements the Redis command ZUNIONSTORE. It stores the union of the
+	// sorted set and others in a new sorted set with the given name, and returns it.
+	// If name already exists, it is overwritten.
+	//
+	// See https://redis.io/commands/zunionstore.
+	UnionStore(name string, options StoreOptions, others ...SortedSet) (SortedSet, error)
+
+	// WithContext returns a copy of the SortedSet that uses ctx for its commands,
+	// including the commands of its base Type. If ctx is done before a command is sent,
+	// ctx.Err() is returned. If ctx has a deadline, it is used as the timeout for the
+	// reply.
+	WithContext(ctx context.Context) SortedSet
+}
+
+type redisSortedSet struct {
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
+}
+
+// NewRedisSortedSet creates a Redis implementation of SortedSet given redigo connection conn
+// and name. The Redis key used to identify the SortedSet will be name.
+func NewRedisSortedSet(conn redis.Conn, name string) SortedSet {
+	return NewRedisSortedSetFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisSortedSetFromProvider creates a Redis implementation of SortedSet given ConnProvider
+// p and name. Each command borrows a connection from p. The Redis key used to identify the
+// SortedSet will be name.
+func NewRedisSortedSetFromProvider(p redistypes.ConnProvider, name string) SortedSet {
+	return &redisSortedSet{
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
+	}
+}
+
+func (r redisSortedSet) Base() redistypes.Type {
+	return r.base
+}
+
+func (r *redisSortedSet) Add(members []Member, options ...AddOption) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZADD", addArgs(r.Base().Name(), members, options)...))
+}
+
+func (r *redisSortedSet) AddIncr(member string, increment float64, options ...AddOption) (float64, bool, error) {
+	args := []interface{}{r.Base().Name()}
+	for _, option := range options {
+		args = append(args, string(option))
+	}
+	args = append(args, "INCR", increment, member)
+
+	score, err := redis.Float64(internal.Do(r.ctx, r.provider, "ZADD", args...))
+	if err == redis.ErrNil {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
+func (r *redisSortedSet) BlockingPopMax(timeout time.Duration) (Member, error) {
+	return r.blockingPop("BZPOPMAX", timeout)
+}
+
+func (r *redisSortedSet) BlockingPopMin(timeout time.Duration) (Member, error) {
+	return r.blockingPop("BZPOPMIN", timeout)
+}
+
+func (r *redisSortedSet) blockingPop(cmd string, timeout time.Duration) (Member, error) {
+	seconds := int64(timeout.Seconds())
+	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return Member{}, errors.New("Duration is not a multiple of one second")
+	}
+
+	values, err := redis.Values(internal.BlockingDo(r.ctx, r.provider, timeout, cmd, func(seconds int64) []interface{} {
+		return []interface{}{r.Base().Name(), seconds}
+	}))
+	if err != nil {
+		return Member{}, err
+	} else if len(values) != 3 {
+		return Member{}, errors.New("Unexpected response length")
+	}
+
+	members, err := memberSlice(values[1:], nil)
+	if err != nil {
+		return Member{}, err
+	}
+	return members[0], nil
+}
+
+func (r *redisSortedSet) Card() (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZCARD", r.Base().Name()))
+}
+
+func (r *redisSortedSet) CloneTo(name string) (SortedSet, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisSortedSetFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisSortedSet) IncrBy(member string, increment float64) (float64, error) {
+	return redis.Float64(internal.Do(r.ctx, r.provider, "ZINCRBY", r.Base().Name(), increment, member))
+}
+
+func (r *redisSortedSet) InterStore(name string, options StoreOptions, others ...SortedSet) (SortedSet, error) {
+	return r.store("ZINTERSTORE", name, options, others)
+}
+
+func (r *redisSortedSet) MultiScore(members ...string) (map[string]float64, error) {
+	args := memberArgs(r.Base().Name(), members)
+
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "ZMSCORE", args...))
+	if err != nil {
+		return nil, err
+	} else if len(values) != len(members) {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	scores := make(map[string]float64, len(members))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		score, err := redis.Float64(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		scores[members[i]] = score
+	}
+	return scores, nil
+}
+
+func (r *redisSortedSet) PopMax(count int64) ([]Member, error) {
+	return memberSlice(internal.Do(r.ctx, r.provider, "ZPOPMAX", r.Base().Name(), count))
+}
+
+func (r *redisSortedSet) PopMin(count int64) ([]Member, error) {
+	return memberSlice(internal.Do(r.ctx, r.provider, "ZPOPMIN", r.Base().Name(), count))
+}
+
+func (r *redisSortedSet) Range(start, stop interface{}, options RangeOptions) ([]string, error) {
+	args := rangeArgs(r.Base().Name(), start, stop, options)
+	return redis.Strings(internal.Do(r.ctx, r.provider, "ZRANGE", args...))
+}
+
+func (r *redisSortedSet) RangeWithScores(start, stop interface{}, options RangeOptions) ([]Member, error) {
+	args := append(rangeArgs(r.Base().Name(), start, stop, options), "WITHSCORES")
+	return memberSlice(internal.Do(r.ctx, r.provider, "ZRANGE", args...))
+}
+
+func (r *redisSortedSet) Rank(member string) (uint64, error) {
+	return rank(internal.Do(r.ctx, r.provider, "ZRANK", r.Base().Name(), member))
+}
+
+func (r *redisSortedSet) Remove(members ...string) (uint64, error) {
+	args := memberArgs(r.Base().Name(), members)
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZREM", args...))
+}
+
+func (r *redisSortedSet) RemoveRangeByLex(min, max string) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZREMRANGEBYLEX", r.Base().Name(), min, max))
+}
+
+func (r *redisSortedSet) RemoveRangeByRank(start, stop int64) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZREMRANGEBYRANK", r.Base().Name(), start, stop))
+}
+
+func (r *redisSortedSet) RemoveRangeByScore(min, max interface{}) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "ZREMRANGEBYSCORE", r.Base().Name(), min, max))
+}
+
+func (r *redisSortedSet) RevRank(member string) (uint64, error) {
+	return rank(internal.Do(r.ctx, r.provider, "ZREVRANK", r.Base().Name(), member))
+}
+
+func (r *redisSortedSet) Score(member string) (float64, error) {
+	score, err := redis.Float64(internal.Do(r.ctx, r.provider, "ZSCORE", r.Base().Name(), member))
+	if err == redis.ErrNil {
+		return 0, ErrMemberNotFound
+	}
+	return score, err
+}
+
+func (r *redisSortedSet) UnionStore(name string, options StoreOptions, others ...SortedSet) (SortedSet, error) {
+	return r.store("ZUNIONSTORE", name, options, others)
+}
+
+func (r *redisSortedSet) store(cmd, name string, options StoreOptions, others []SortedSet) (SortedSet, error) {
+	args := []interface{}{name, 1 + len(others), r.Base().Name()}
+	for _, other := range others {
+		args = append(args, other.Base().Name())
+	}
+	if len(options.Weights) > 0 {
+		args = append(args, "WEIGHTS")
+		for _, weight := range options.Weights {
+			args = append(args, weight)
+		}
+	}
+	if options.Aggregate != "" {
+		args = append(args, "AGGREGATE", string(options.Aggregate))
+	}
+
+	if _, err := internal.Do(r.ctx, r.provider, cmd, args...); err != nil {
+		return nil, err
+	}
+
+	return NewRedisSortedSetFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisSortedSet) WithContext(ctx context.Context) SortedSet {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisSortedSet{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// addArgs returns the arguments to ZADD for members with options.
+func addArgs(name string, members []Member, options []AddOption) []interface{} {
+	args := make([]interface{}, 0, 1+len(options)+2*len(members))
+	args = append(args, name)
+	for _, option := range options {
+		args = append(args, string(option))
+	}
+	for _, member := range members {
+		args = append(args, member.Score, member.Value)
+	}
+	return args
+}
+
+// memberArgs returns the arguments to a command that takes name followed by members.
+func memberArgs(name string, members []string) []interface{} {
+	args := make([]interface{}, 0, 1+len(members))
+	args = append(args, name)
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return args
+}
+
+// rangeArgs returns the arguments to ZRANGE, not including WITHSCORES.
+func rangeArgs(name string, start, stop interface{}, options RangeOptions) []interface{} {
+	args := []interface{}{name, start, stop}
+	if options.By != ByRank {
+		args = append(args, string(options.By))
+	}
+	if options.Reverse {
+		args = append(args, "REV")
+	}
+	if options.Count != 0 {
+		args = append(args, "LIMIT", options.Offset, options.Count)
+	}
+	return args
+}
+
+// rank converts the reply to ZRANK or ZREVRANK.
+func rank(reply interface{}, err error) (uint64, error) {
+	value, err := redis.Uint64(reply, err)
+	if err == redis.ErrNil {
+		return 0, ErrMemberNotFound
+	}
+	return value, err
+}
+
+// memberSlice converts a reply made up of pairs of members and scores to a slice of
+// Member.
+func memberSlice(reply interface{}, err error) ([]Member, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	} else if len(values)%2 != 0 {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	members := make([]Member, len(values)/2)
+	for i := range members {
+		value, err := redis.String(values[2*i], nil)
+		if err != nil {
+			return nil, err
+		}
+		score, err := redis.Float64(values[2*i+1], nil)
+		if err != nil {
+			return nil, err
+		}
+		members[i] = Member{Value: value, Score: score}
+	}
+	return members, nil
+}
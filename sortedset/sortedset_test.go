@@ -0,0 +1,499 @@
+package sortedset_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/sortedset"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func ExampleNewRedisSortedSet() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	leaderboard := sortedset.NewRedisSortedSet(conn, "my_leaderboard")
+	defer leaderboard.Base().Delete()
+
+	_, _ = leaderboard.Add([]sortedset.Member{
+		{Value: "alice", Score: 120},
+		{Value: "bob", Score: 95},
+		{Value: "carol", Score: 150},
+	})
+
+	top, _ := leaderboard.RangeWithScores(0, 1, sortedset.RangeOptions{Reverse: true})
+	for _, member := range top {
+		fmt.Println(member.Value, member.Score)
+	}
+
+	// Output:
+	// carol 150
+	// alice 120
+}
+
+// members returns the members of s from the lowest score to the highest.
+func members(t *testing.T, s sortedset.SortedSet) []sortedset.Member {
+	values, err := s.RangeWithScores(0, -1, sortedset.RangeOptions{})
+	assert.Nil(t, err)
+	return values
+}
+
+func TestNewRedisSortedSetFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	s := sortedset.NewRedisSortedSetFromProvider(pool, test.RandomKey())
+	defer s.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.IncrBy(fmt.Sprint(i), 1)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	value, err := s.Card()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
+func TestRedisSortedSet_Add(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+
+	t.Run("CH", func(t *testing.T) {
+		value, err := s.Add([]sortedset.Member{{Value: "a", Score: 3}, {Value: "c", Score: 1}}, sortedset.AddCH)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+
+	t.Run("NX", func(t *testing.T) {
+		value, err := s.Add([]sortedset.Member{{Value: "a", Score: 10}, {Value: "d", Score: 4}}, sortedset.AddNX)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+
+	t.Run("XX", func(t *testing.T) {
+		value, err := s.Add([]sortedset.Member{{Value: "b", Score: 5}, {Value: "e", Score: 5}}, sortedset.AddXX)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value)
+	})
+
+	t.Run("GT", func(t *testing.T) {
+		value, err := s.Add([]sortedset.Member{{Value: "a", Score: 0}, {Value: "c", Score: 6}},
+			sortedset.AddGT, sortedset.AddCH)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := s.Add([]sortedset.Member{{Value: "a", Score: 1}}, sortedset.AddNX, sortedset.AddXX)
+		assert.NotNil(t, err)
+	})
+
+	assert.Equal(t, []sortedset.Member{
+		{Value: "a", Score: 3},
+		{Value: "d", Score: 4},
+		{Value: "b", Score: 5},
+		{Value: "c", Score: 6},
+	}, members(t, s))
+}
+
+func TestRedisSortedSet_AddIncr(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	score, ok, err := s.AddIncr("a", 1.5)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, score)
+
+	score, ok, err = s.AddIncr("a", 1, sortedset.AddNX)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, score)
+
+	score, ok, err = s.AddIncr("a", 1, sortedset.AddXX)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, score)
+}
+
+func TestRedisSortedSet_BlockingPopMax(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := s.BlockingPopMax(time.Millisecond)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := s.BlockingPopMax(time.Second)
+		assert.Equal(t, redis.ErrNil, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+
+		value, err := s.BlockingPopMax(0)
+		assert.Nil(t, err)
+		assert.Equal(t, sortedset.Member{Value: "b", Score: 2}, value)
+	})
+}
+
+func TestRedisSortedSet_BlockingPopMin(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+
+	value, err := s.BlockingPopMin(0)
+	assert.Nil(t, err)
+	assert.Equal(t, sortedset.Member{Value: "a", Score: 1}, value)
+}
+
+func TestRedisSortedSet_Card(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	value, err := s.Card()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+
+	value, err = s.Card()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+}
+
+func TestRedisSortedSet_CloneTo(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := s.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}})
+
+		clone, err := s.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = s.Add([]sortedset.Member{{Value: "b", Score: 2}})
+
+		assert.Equal(t, []sortedset.Member{{Value: "a", Score: 1}}, members(t, clone))
+	})
+}
+
+func TestRedisSortedSet_IncrBy(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	value, err := s.IncrBy("a", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, value)
+
+	value, err = s.IncrBy("a", -0.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, value)
+}
+
+func TestRedisSortedSet_InterStore(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	other := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer other.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+	_, _ = other.Add([]sortedset.Member{{Value: "b", Score: 3}, {Value: "c", Score: 4}})
+
+	t.Run("weights", func(t *testing.T) {
+		inter, err := s.InterStore(test.RandomKey(), sortedset.StoreOptions{Weights: []float64{2, 1}}, other)
+		assert.Nil(t, err)
+		defer inter.Base().Delete()
+
+		assert.Equal(t, []sortedset.Member{{Value: "b", Score: 7}}, members(t, inter))
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		inter, err := s.InterStore(test.RandomKey(), sortedset.StoreOptions{Aggregate: sortedset.AggregateMin}, other)
+		assert.Nil(t, err)
+		defer inter.Base().Delete()
+
+		assert.Equal(t, []sortedset.Member{{Value: "b", Score: 2}}, members(t, inter))
+	})
+}
+
+func TestRedisSortedSet_MultiScore(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2.5}})
+
+	scores, err := s.MultiScore("a", "b", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]float64{"a": 1, "b": 2.5}, scores)
+}
+
+func TestRedisSortedSet_PopMax(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		values, err := s.PopMax(1)
+		assert.Nil(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}, {Value: "c", Score: 3}})
+
+		values, err := s.PopMax(2)
+		assert.Nil(t, err)
+		assert.Equal(t, []sortedset.Member{{Value: "c", Score: 3}, {Value: "b", Score: 2}}, values)
+	})
+}
+
+func TestRedisSortedSet_PopMin(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}, {Value: "c", Score: 3}})
+
+	values, err := s.PopMin(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}}, values)
+}
+
+func TestRedisSortedSet_Range(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{
+		{Value: "a", Score: 1},
+		{Value: "b", Score: 2},
+		{Value: "c", Score: 3},
+		{Value: "d", Score: 4},
+	})
+
+	t.Run("rank", func(t *testing.T) {
+		values, err := s.Range(1, 2, sortedset.RangeOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"b", "c"}, values)
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		values, err := s.Range(0, 1, sortedset.RangeOptions{Reverse: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"d", "c"}, values)
+	})
+
+	t.Run("score", func(t *testing.T) {
+		values, err := s.Range("(1", "+inf", sortedset.RangeOptions{By: sortedset.ByScore})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"b", "c", "d"}, values)
+	})
+
+	t.Run("score reverse with limit", func(t *testing.T) {
+		values, err := s.Range(4, 1, sortedset.RangeOptions{
+			By:      sortedset.ByScore,
+			Reverse: true,
+			Offset:  1,
+			Count:   2,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"c", "b"}, values)
+	})
+
+	t.Run("lex", func(t *testing.T) {
+		lex := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+		defer lex.Base().Delete()
+
+		_, _ = lex.Add([]sortedset.Member{{Value: "a"}, {Value: "b"}, {Value: "c"}})
+
+		values, err := lex.Range("[b", "+", sortedset.RangeOptions{By: sortedset.ByLex})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"b", "c"}, values)
+	})
+
+	t.Run("with scores", func(t *testing.T) {
+		values, err := s.RangeWithScores(3, 4, sortedset.RangeOptions{By: sortedset.ByScore})
+		assert.Nil(t, err)
+		assert.Equal(t, []sortedset.Member{{Value: "c", Score: 3}, {Value: "d", Score: 4}}, values)
+	})
+}
+
+func TestRedisSortedSet_Rank(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}, {Value: "c", Score: 3}})
+
+	value, err := s.Rank("b")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	value, err = s.RevRank("c")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	_, err = s.Rank("d")
+	assert.Equal(t, sortedset.ErrMemberNotFound, err)
+
+	_, err = s.RevRank("d")
+	assert.Equal(t, sortedset.ErrMemberNotFound, err)
+}
+
+func TestRedisSortedSet_Remove(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+
+	value, err := s.Remove("a", "c")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	assert.Equal(t, []sortedset.Member{{Value: "b", Score: 2}}, members(t, s))
+}
+
+func TestRedisSortedSet_RemoveRange(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("rank", func(t *testing.T) {
+		_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}, {Value: "c", Score: 3}})
+
+		value, err := s.RemoveRangeByRank(0, 1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+		assert.Equal(t, []sortedset.Member{{Value: "c", Score: 3}}, members(t, s))
+	})
+
+	t.Run("score", func(t *testing.T) {
+		_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+
+		value, err := s.RemoveRangeByScore("(1", 2)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+		assert.Equal(t, []sortedset.Member{{Value: "a", Score: 1}, {Value: "c", Score: 3}}, members(t, s))
+	})
+
+	t.Run("lex", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		_, _ = s.Add([]sortedset.Member{{Value: "a"}, {Value: "b"}, {Value: "c"}})
+
+		value, err := s.RemoveRangeByLex("[a", "(c")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+		assert.Equal(t, []sortedset.Member{{Value: "c"}}, members(t, s))
+	})
+}
+
+func TestRedisSortedSet_Score(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	_, err := s.Score("a")
+	assert.Equal(t, sortedset.ErrMemberNotFound, err)
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1.25}})
+
+	value, err := s.Score("a")
+	assert.Nil(t, err)
+	assert.Equal(t, 1.25, value)
+}
+
+func TestRedisSortedSet_UnionStore(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	other := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer other.Base().Delete()
+
+	_, _ = s.Add([]sortedset.Member{{Value: "a", Score: 1}, {Value: "b", Score: 2}})
+	_, _ = other.Add([]sortedset.Member{{Value: "b", Score: 3}, {Value: "c", Score: 4}})
+
+	union, err := s.UnionStore(test.RandomKey(), sortedset.StoreOptions{
+		Weights:   []float64{10, 1},
+		Aggregate: sortedset.AggregateMax,
+	}, other)
+	assert.Nil(t, err)
+	defer union.Base().Delete()
+
+	assert.Equal(t, []sortedset.Member{
+		{Value: "c", Score: 4},
+		{Value: "a", Score: 10},
+		{Value: "b", Score: 20},
+	}, members(t, union))
+}
+
+func TestRedisSortedSet_WithContext(t *testing.T) {
+	s := sortedset.NewRedisSortedSet(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := s.WithContext(ctx).Add([]sortedset.Member{{Value: "a", Score: 1}})
+		assert.Equal(t, context.Canceled, err)
+
+		value, err := s.Card()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, value)
+	})
+
+	t.Run("blocking pop", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := s.WithContext(ctx).BlockingPopMin(0)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,294 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Handler processes an entry delivered by Stream.Consume. If it returns nil, the entry
+// is acknowledged.
+type Handler func(ctx context.Context, entry Entry) error
+
+// DeadLetterHandler processes an entry that was delivered too many times without being
+// acknowledged. deliveries is the number of times it was delivered before. If it returns
+// nil, the entry is acknowledged.
+type DeadLetterHandler func(ctx context.Context, entry Entry, deliveries int64) error
+
+// ConsumeOptions are the options for Stream.Consume. Fields left at their zero value use
+// the defaults.
+type ConsumeOptions struct {
+	// Concurrency is the number of handlers that can run at the same time. The default
+	// is 1.
+	Concurrency int
+
+	// Count is the highest number of entries read or claimed at once. The default is 10.
+	Count int64
+
+	// Block is how long each read waits for new entries. It must be a multiple of one
+	// second. The default is one second.
+	Block time.Duration
+
+	// MinIdle is how long an entry must be pending before it is claimed again. The
+	// default is one minute.
+	MinIdle time.Duration
+
+	// ClaimInterval is how often pending entries are checked for claiming. The default
+	// is MinIdle.
+	ClaimInterval time.Duration
+
+	// MaxDeliveries is the number of times an entry can be delivered before it is passed
+	// to DeadLetter instead of the handler. If it is 0, entries are retried forever.
+	MaxDeliveries int64
+
+	// DeadLetter processes the entries that reached MaxDeliveries. If it is nil, they
+	// are acknowledged and dropped.
+	DeadLetter DeadLetterHandler
+}
+
+const (
+	defaultConsumeCount   = 10
+	defaultConsumeBlock   = time.Second
+	defaultConsumeMinIdle = time.Minute
+)
+
+// withDefaults returns the options with the defaults filled in.
+func (o ConsumeOptions) withDefaults() ConsumeOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.Count <= 0 {
+		o.Count = defaultConsumeCount
+	}
+	if o.Block <= 0 {
+		o.Block = defaultConsumeBlock
+	}
+	if o.MinIdle <= 0 {
+		o.MinIdle = defaultConsumeMinIdle
+	}
+	if o.ClaimInterval <= 0 {
+		o.ClaimInterval = o.MinIdle
+	}
+	return o
+}
+
+// consumeJob is an entry passed from Consume to a worker.
+type consumeJob struct {
+	entry      Entry
+	deliveries int64
+	dead       bool
+}
+
+// consumeResult is reported by a worker for each job it ran. ack is true if the entry
+// should be acknowledged.
+type consumeResult struct {
+	id  ID
+	ack bool
+}
+
+// run processes the entry with handler, or with deadLetter if the entry is dead.
+func (j consumeJob) run(ctx context.Context, handler Handler, deadLetter DeadLetterHandler) error {
+	if !j.dead {
+		return handler(ctx, j.entry)
+	} else if deadLetter == nil {
+		return nil
+	}
+	return deadLetter(ctx, j.entry, j.deliveries)
+}
+
+func (r *redisStream) Consume(ctx context.Context, group, consumer string, handler Handler, options ConsumeOptions) error {
+	options = options.withDefaults()
+	s := r.WithContext(ctx)
+
+	// Only this goroutine sends commands. The workers report the entries they processed
+	// on done, which has room for one entry per worker so they never block on it for long.
+	// An entry stays in inFlight from the time it is dispatched until its result is
+	// collected, so it is not claimed again while a worker is still handling it.
+	jobs := make(chan consumeJob)
+	done := make(chan consumeResult, options.Concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				err := j.run(ctx, handler, options.DeadLetter)
+				done <- consumeResult{id: j.entry.ID, ack: err == nil}
+			}
+		}()
+	}
+
+	var acks []ID
+	var err error
+	inFlight := make(map[ID]bool)
+	nextClaim := time.Now()
+	for err == nil {
+		acks = collectDone(done, inFlight, acks)
+		if len(acks) > 0 {
+			if _, err = s.Ack(group, acks...); err != nil {
+				break
+			}
+			acks = acks[:0]
+		}
+
+		var batch []consumeJob
+		if !time.Now().Before(nextClaim) {
+			nextClaim = time.Now().Add(options.ClaimInterval)
+			var deleted []ID
+			if batch, deleted, err = claimJobs(s, group, consumer, options, inFlight); err != nil {
+				break
+			}
+			acks = append(acks, deleted...)
+		}
+		if len(batch) == 0 {
+			var entries []Entry
+			if entries, err = s.BlockingReadGroup(group, consumer, options.Count, options.Block); err != nil {
+				break
+			}
+			for _, e := range entries {
+				batch = append(batch, consumeJob{entry: e})
+			}
+		}
+
+		acks, err = dispatchJobs(ctx, jobs, done, batch, inFlight, acks)
+	}
+
+	close(jobs)
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for res := range done {
+		if res.ack {
+			acks = append(acks, res.id)
+		}
+	}
+	if len(acks) > 0 {
+		// ctx may be done, so the last entries are acknowledged without it.
+		_, _ = r.WithContext(context.Background()).Ack(group, acks...)
+	}
+	return err
+}
+
+// claimJobs claims the entries that have been pending in group for at least
+// options.MinIdle, and returns them as jobs. Entries in inFlight are still being handled
+// by a worker and are not claimed. Entries that were deleted from the stream are
+// returned separately so they can be acknowledged.
+func claimJobs(s Stream, group, consumer string, options ConsumeOptions, inFlight map[ID]bool) ([]consumeJob, []ID, error) {
+	// Ask for enough entries that options.Count remain once the ones in flight are left out.
+	count := options.Count + int64(len(inFlight))
+	pending, err := s.PendingEntries(group, MinID, MaxID, count, PendingOptions{MinIdle: options.MinIdle})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ids []ID
+	deliveries := make(map[ID]int64, len(pending))
+	for _, p := range pending {
+		if inFlight[p.ID] || int64(len(ids)) == options.Count {
+			continue
+		}
+		ids = append(ids, p.ID)
+		deliveries[p.ID] = p.Deliveries
+	}
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	entries, err := s.Claim(group, consumer, options.MinIdle, ids...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var jobs []consumeJob
+	claimed := make(map[ID]bool, len(entries))
+	for _, e := range entries {
+		if e.Fields == nil {
+			continue
+		}
+		claimed[e.ID] = true
+
+		n := deliveries[e.ID]
+		jobs = append(jobs, consumeJob{
+			entry:      e,
+			deliveries: n,
+			dead:       options.MaxDeliveries > 0 && n >= options.MaxDeliveries,
+		})
+	}
+
+	deleted, err := deletedEntries(s, group, consumer, ids, claimed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return jobs, deleted, nil
+}
+
+// deletedEntries returns the IDs in ids that XCLAIM didn't return an entry for because
+// they were deleted from the stream. Before Redis 7.0, XCLAIM still claims a deleted
+// entry but replies with nil for it, which entrySlice leaves out. Since Redis 7.0 it
+// removes the entry from the pending list instead. IDs that are missing from claimed
+// because another consumer claimed them first are not returned.
+//
+// Each missing ID is looked up on its own. A range over all of them could also hold
+// other pending entries, and a reply cut short by its count would leave the IDs past
+// the cut without an owner.
+func deletedEntries(s Stream, group, consumer string, ids []ID, claimed map[ID]bool) ([]ID, error) {
+	var deleted []ID
+	for _, id := range ids {
+		if claimed[id] {
+			continue
+		}
+
+		pending, err := s.PendingEntries(group, id, id, 1, PendingOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) == 0 || pending[0].Consumer == consumer {
+			deleted = append(deleted, id)
+		}
+	}
+	return deleted, nil
+}
+
+// dispatchJobs sends batch to the workers on jobs, adding each entry to inFlight. While
+// it waits, it collects the results reported on done like collectDone. It returns early
+// with ctx.Err() if ctx is done.
+func dispatchJobs(ctx context.Context, jobs chan<- consumeJob, done <-chan consumeResult, batch []consumeJob, inFlight map[ID]bool, acks []ID) ([]ID, error) {
+	for _, j := range batch {
+		for sent := false; !sent; {
+			select {
+			case jobs <- j:
+				inFlight[j.entry.ID] = true
+				sent = true
+			case res := <-done:
+				acks = collectResult(res, inFlight, acks)
+			case <-ctx.Done():
+				return acks, ctx.Err()
+			}
+		}
+	}
+	return acks, nil
+}
+
+// collectDone collects the results waiting on done without blocking. Each entry is
+// removed from inFlight, and appended to acks if it should be acknowledged.
+func collectDone(done <-chan consumeResult, inFlight map[ID]bool, acks []ID) []ID {
+	for {
+		select {
+		case res := <-done:
+			acks = collectResult(res, inFlight, acks)
+		default:
+			return acks
+		}
+	}
+}
+
+// collectResult removes res from inFlight, and appends it to acks if it should be
+// acknowledged.
+func collectResult(res consumeResult, inFlight map[ID]bool, acks []ID) []ID {
+	delete(inFlight, res.id)
+	if res.ack {
+		acks = append(acks, res.id)
+	}
+	return acks
+}
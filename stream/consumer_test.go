@@ -0,0 +1,266 @@
+package stream_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// consumeContext returns a context that is cancelled after 10 seconds, so a failing test
+// doesn't block forever. It has no deadline, so conn can still be used once it is done.
+func consumeContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Second, cancel)
+	return ctx, cancel
+}
+
+// assertNoPending asserts that s has no entries pending in group.
+func assertNoPending(t *testing.T, s stream.Stream, group string) {
+	summary, err := s.Pending(group)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, summary.Count)
+}
+
+// claimRaceConn sends everything to the embedded connection, but before the first XCLAIM
+// it claims id for the consumer "other", as if that consumer claimed it first.
+type claimRaceConn struct {
+	redis.Conn
+	key     string
+	id      stream.ID
+	claimed bool
+}
+
+func (c *claimRaceConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if cmd == "XCLAIM" && !c.claimed {
+		c.claimed = true
+		if _, err := c.Conn.Do("XCLAIM", c.key, "group", "other", 0, c.id.String()); err != nil {
+			return nil, err
+		}
+	}
+	return c.Conn.Do(cmd, args...)
+}
+
+func (c *claimRaceConn) DoWithTimeout(timeout time.Duration, cmd string, args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, cmd, args...)
+}
+
+func TestRedisStream_Consume(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := newGroup(t, 5)
+		defer s.Base().Delete()
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		var mu sync.Mutex
+		var handled []stream.ID
+		err := s.Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			handled = append(handled, entry.ID)
+			if len(handled) == 5 {
+				cancel()
+			}
+			return nil
+		}, stream.ConsumeOptions{Count: 2})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, []stream.ID{{Millis: 1}, {Millis: 2}, {Millis: 3}, {Millis: 4}, {Millis: 5}}, handled)
+		assertNoPending(t, s, "group")
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		s := newGroup(t, 1)
+		defer s.Base().Delete()
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		attempts := 0
+		err := s.Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("failed")
+			}
+
+			cancel()
+			return nil
+		}, stream.ConsumeOptions{MinIdle: 500 * time.Millisecond})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 2, attempts)
+		assertNoPending(t, s, "group")
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		s := newGroup(t, 1)
+		defer s.Base().Delete()
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		attempts := 0
+		var dead []int64
+		err := s.Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			attempts++
+			return errors.New("failed")
+		}, stream.ConsumeOptions{
+			MinIdle:       500 * time.Millisecond,
+			MaxDeliveries: 2,
+			DeadLetter: func(ctx context.Context, entry stream.Entry, deliveries int64) error {
+				dead = append(dead, deliveries)
+				cancel()
+				return nil
+			},
+		})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, []int64{2}, dead)
+		assertNoPending(t, s, "group")
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		s := newGroup(t, 3)
+		defer s.Base().Delete()
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		var mu sync.Mutex
+		running, maxRunning, handled := 0, 0, 0
+		err := s.Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(200 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			running--
+			handled++
+			if handled == 3 {
+				cancel()
+			}
+			return nil
+		}, stream.ConsumeOptions{Concurrency: 3})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 3, maxRunning)
+		assertNoPending(t, s, "group")
+	})
+
+	t.Run("deleted pending entry", func(t *testing.T) {
+		s := newGroup(t, 2)
+		defer s.Base().Delete()
+
+		_, err := s.ReadGroup("group", "other", 0)
+		assert.Nil(t, err)
+		_, err = s.Delete(stream.ID{Millis: 1})
+		assert.Nil(t, err)
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		var handled []stream.ID
+		err = s.Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			handled = append(handled, entry.ID)
+			cancel()
+			return nil
+		}, stream.ConsumeOptions{MinIdle: 50 * time.Millisecond})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, []stream.ID{{Millis: 2}}, handled)
+		assertNoPending(t, s, "group")
+	})
+
+	t.Run("entry claimed by another consumer", func(t *testing.T) {
+		s := newGroup(t, 6)
+		defer s.Base().Delete()
+
+		// 1 is deleted, 2 to 4 are pending but not idle, and 5 is claimed by "other" just
+		// before Consume claims it.
+		_, err := s.ReadGroup("group", "first", 5)
+		assert.Nil(t, err)
+		time.Sleep(100 * time.Millisecond)
+		_, err = s.Claim("group", "busy", 0, stream.ID{Millis: 2}, stream.ID{Millis: 3}, stream.ID{Millis: 4})
+		assert.Nil(t, err)
+		_, err = s.Delete(stream.ID{Millis: 1})
+		assert.Nil(t, err)
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		race := &claimRaceConn{Conn: conn, key: s.Base().Name(), id: stream.ID{Millis: 5}}
+		var handled []stream.ID
+		err = stream.NewRedisStream(race, s.Base().Name()).Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			handled = append(handled, entry.ID)
+			cancel()
+			return nil
+		}, stream.ConsumeOptions{MinIdle: 50 * time.Millisecond})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, []stream.ID{{Millis: 6}}, handled)
+
+		pending, err := s.PendingEntries("group", stream.MinID, stream.MaxID, 10, stream.PendingOptions{})
+		assert.Nil(t, err)
+		owners := make(map[stream.ID]string)
+		for _, p := range pending {
+			owners[p.ID] = p.Consumer
+		}
+		assert.Equal(t, map[stream.ID]string{
+			{Millis: 2}: "busy",
+			{Millis: 3}: "busy",
+			{Millis: 4}: "busy",
+			{Millis: 5}: "other",
+		}, owners)
+	})
+
+	t.Run("slow handler", func(t *testing.T) {
+		s := newGroup(t, 1)
+		defer s.Base().Delete()
+
+		ctx, cancel := consumeContext()
+		defer cancel()
+
+		// The entry is idle for longer than MinIdle while the handler runs, but it must
+		// not be claimed again for the other worker.
+		var mu sync.Mutex
+		calls := 0
+		err := s.Consume(ctx, "group", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+
+			time.Sleep(1500 * time.Millisecond)
+			cancel()
+			return nil
+		}, stream.ConsumeOptions{Concurrency: 2, MinIdle: 200 * time.Millisecond})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 1, calls)
+		assertNoPending(t, s, "group")
+	})
+
+	t.Run("non-existing group", func(t *testing.T) {
+		s := newGroup(t, 1)
+		defer s.Base().Delete()
+
+		err := s.Consume(context.Background(), "other", "consumer", func(ctx context.Context, entry stream.Entry) error {
+			return nil
+		}, stream.ConsumeOptions{})
+		assert.NotNil(t, err)
+	})
+}
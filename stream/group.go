@@ -0,0 +1,213 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// PendingSummary is the summary of the entries pending in a consumer group, which
+// have been delivered to a consumer but not acknowledged.
+type PendingSummary struct {
+	// Count is the number of pending entries.
+	Count int64
+
+	// Lowest and Highest are the lowest and highest IDs of the pending entries.
+	Lowest  ID
+	Highest ID
+
+	// Consumers maps the name of each consumer with pending entries to their number.
+	Consumers map[string]int64
+}
+
+// PendingEntry is an entry pending in a consumer group.
+type PendingEntry struct {
+	ID ID
+
+	// Consumer is the name of the consumer the entry was delivered to.
+	Consumer string
+
+	// Idle is the time since the entry was last delivered.
+	Idle time.Duration
+
+	// Deliveries is the number of times the entry was delivered.
+	Deliveries int64
+}
+
+// PendingOptions are the options for Stream.PendingEntries.
+type PendingOptions struct {
+	// MinIdle only returns the entries that have been idle for at least MinIdle.
+	MinIdle time.Duration
+
+	// Consumer only returns the entries delivered to Consumer, if it is not empty.
+	Consumer string
+}
+
+func (r *redisStream) Ack(group string, ids ...ID) (uint64, error) {
+	args := []interface{}{r.Base().Name(), group}
+	for _, id := range ids {
+		args = append(args, id.String())
+	}
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "XACK", args...))
+}
+
+func (r *redisStream) AutoClaim(group, consumer string, minIdle time.Duration, start ID, count int64) (ID, []Entry, error) {
+	args := []interface{}{r.Base().Name(), group, consumer, int64(minIdle / time.Millisecond), start.String()}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "XAUTOCLAIM", args...))
+	if err != nil {
+		return ID{}, nil, err
+	} else if len(values) < 2 {
+		return ID{}, nil, errors.New("Unexpected response length")
+	}
+
+	next, err := parseIDReply(values[0], nil)
+	if err != nil {
+		return ID{}, nil, err
+	}
+	entries, err := entrySlice(values[1], nil)
+	if err != nil {
+		return ID{}, nil, err
+	}
+	return next, entries, nil
+}
+
+func (r *redisStream) BlockingReadGroup(group, consumer string, count int64, timeout time.Duration) ([]Entry, error) {
+	seconds := int64(timeout.Seconds())
+	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return nil, errors.New("Duration is not a multiple of one second")
+	}
+
+	return readReply(internal.BlockingDo(r.ctx, r.provider, timeout, "XREADGROUP", func(seconds int64) []interface{} {
+		return readGroupArgs(r.Base().Name(), group, consumer, count, seconds*1000)
+	}))
+}
+
+func (r *redisStream) Claim(group, consumer string, minIdle time.Duration, ids ...ID) ([]Entry, error) {
+	args := []interface{}{r.Base().Name(), group, consumer, int64(minIdle / time.Millisecond)}
+	for _, id := range ids {
+		args = append(args, id.String())
+	}
+	return entrySlice(internal.Do(r.ctx, r.provider, "XCLAIM", args...))
+}
+
+func (r *redisStream) CreateGroup(group string, start ID, mkStream bool) error {
+	args := []interface{}{"CREATE", r.Base().Name(), group, start.String()}
+	if mkStream {
+		args = append(args, "MKSTREAM")
+	}
+	_, err := internal.Do(r.ctx, r.provider, "XGROUP", args...)
+	return err
+}
+
+func (r *redisStream) DestroyGroup(group string) (bool, error) {
+	return redis.Bool(internal.Do(r.ctx, r.provider, "XGROUP", "DESTROY", r.Base().Name(), group))
+}
+
+func (r *redisStream) Pending(group string) (PendingSummary, error) {
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "XPENDING", r.Base().Name(), group))
+	if err != nil {
+		return PendingSummary{}, err
+	} else if len(values) != 4 {
+		return PendingSummary{}, errors.New("Unexpected response length")
+	}
+
+	var summary PendingSummary
+	if summary.Count, err = redis.Int64(values[0], nil); err != nil || summary.Count == 0 {
+		return summary, err
+	}
+	if summary.Lowest, err = parseIDReply(values[1], nil); err != nil {
+		return PendingSummary{}, err
+	}
+	if summary.Highest, err = parseIDReply(values[2], nil); err != nil {
+		return PendingSummary{}, err
+	}
+
+	consumers, err := redis.Values(values[3], nil)
+	if err != nil {
+		return PendingSummary{}, err
+	}
+	summary.Consumers = make(map[string]int64, len(consumers))
+	for _, consumer := range consumers {
+		pair, err := redis.Strings(consumer, nil)
+		if err != nil {
+			return PendingSummary{}, err
+		} else if len(pair) != 2 {
+			return PendingSummary{}, errors.New("Unexpected response length")
+		}
+		if summary.Consumers[pair[0]], err = strconv.ParseInt(pair[1], 10, 64); err != nil {
+			return PendingSummary{}, err
+		}
+	}
+	return summary, nil
+}
+
+func (r *redisStream) PendingEntries(group string, start, end ID, count int64, options PendingOptions) ([]PendingEntry, error) {
+	args := []interface{}{r.Base().Name(), group}
+	if options.MinIdle > 0 {
+		args = append(args, "IDLE", int64(options.MinIdle/time.Millisecond))
+	}
+	args = append(args, start.String(), end.String(), count)
+	if options.Consumer != "" {
+		args = append(args, options.Consumer)
+	}
+
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "XPENDING", args...))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]PendingEntry, len(values))
+	for i, value := range values {
+		fields, err := redis.Values(value, nil)
+		if err != nil {
+			return nil, err
+		} else if len(fields) != 4 {
+			return nil, errors.New("Unexpected response length")
+		}
+
+		var idle int64
+		if entries[i].ID, err = parseIDReply(fields[0], nil); err != nil {
+			return nil, err
+		}
+		if entries[i].Consumer, err = redis.String(fields[1], nil); err != nil {
+			return nil, err
+		}
+		if idle, err = redis.Int64(fields[2], nil); err != nil {
+			return nil, err
+		}
+		if entries[i].Deliveries, err = redis.Int64(fields[3], nil); err != nil {
+			return nil, err
+		}
+		entries[i].Idle = time.Duration(idle) * time.Millisecond
+	}
+	return entries, nil
+}
+
+func (r *redisStream) ReadGroup(group, consumer string, count int64) ([]Entry, error) {
+	return readReply(internal.Do(r.ctx, r.provider, "XREADGROUP", readGroupArgs(r.Base().Name(), group, consumer, count, -1)...))
+}
+
+func (r *redisStream) SetGroupID(group string, id ID) error {
+	_, err := internal.Do(r.ctx, r.provider, "XGROUP", "SETID", r.Base().Name(), group, id.String())
+	return err
+}
+
+// readGroupArgs returns the arguments to XREADGROUP for entries that were never
+// delivered. If block is negative, the BLOCK option isn't sent.
+func readGroupArgs(name, group, consumer string, count, block int64) []interface{} {
+	args := []interface{}{"GROUP", group, consumer}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	if block >= 0 {
+		args = append(args, "BLOCK", block)
+	}
+	return append(args, "STREAMS", name, ">")
+}
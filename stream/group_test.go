@@ -0,0 +1,261 @@
+package stream_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGroup returns a stream with entries 1-0 to n-0 and a group that delivers all of them.
+func newGroup(t *testing.T, n int) stream.Stream {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	addEntries(t, s, n)
+	assert.Nil(t, s.CreateGroup("group", stream.MinID, false))
+	return s
+}
+
+func TestRedisStream_Ack(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	_, err := s.ReadGroup("group", "consumer", 0)
+	assert.Nil(t, err)
+
+	value, err := s.Ack("group", stream.ID{Millis: 1}, stream.ID{Millis: 3}, stream.ID{Millis: 5})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+
+	summary, err := s.Pending("group")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, summary.Count)
+}
+
+func TestRedisStream_AutoClaim(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	_, err := s.ReadGroup("group", "consumer1", 0)
+	assert.Nil(t, err)
+
+	t.Run("not idle", func(t *testing.T) {
+		next, entries, err := s.AutoClaim("group", "consumer2", time.Minute, stream.MinID, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, stream.MinID, next)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		next, entries, err := s.AutoClaim("group", "consumer2", 0, stream.MinID, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, stream.ID{Millis: 3}, next)
+		assert.Equal(t, []stream.ID{{Millis: 1}, {Millis: 2}}, ids(entries))
+
+		next, entries, err = s.AutoClaim("group", "consumer2", 0, next, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, stream.MinID, next)
+		assert.Equal(t, []stream.ID{{Millis: 3}}, ids(entries))
+
+		summary, err := s.Pending("group")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{"consumer2": 3}, summary.Consumers)
+	})
+}
+
+func TestRedisStream_BlockingReadGroup(t *testing.T) {
+	s := newGroup(t, 1)
+	defer s.Base().Delete()
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := s.BlockingReadGroup("group", "consumer", 0, time.Millisecond)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("existing entries", func(t *testing.T) {
+		entries, err := s.BlockingReadGroup("group", "consumer", 0, time.Second)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 1}}, ids(entries))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		entries, err := s.BlockingReadGroup("group", "consumer", 0, time.Second)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestRedisStream_Claim(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	_, err := s.ReadGroup("group", "consumer1", 0)
+	assert.Nil(t, err)
+
+	t.Run("not idle", func(t *testing.T) {
+		entries, err := s.Claim("group", "consumer2", time.Minute, stream.ID{Millis: 1})
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		entries, err := s.Claim("group", "consumer2", 0, stream.ID{Millis: 1}, stream.ID{Millis: 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.Entry{
+			{ID: stream.ID{Millis: 1}, Fields: map[string]string{"n": "1"}},
+			{ID: stream.ID{Millis: 3}, Fields: map[string]string{"n": "3"}},
+		}, entries)
+
+		summary, err := s.Pending("group")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{"consumer1": 1, "consumer2": 2}, summary.Consumers)
+	})
+}
+
+func TestRedisStream_CreateGroup(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		err := s.CreateGroup("group", stream.MinID, false)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("mkstream", func(t *testing.T) {
+		err := s.CreateGroup("group", stream.MinID, true)
+		assert.Nil(t, err)
+
+		info, err := s.Info()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, info.Groups)
+	})
+
+	t.Run("existing group", func(t *testing.T) {
+		err := s.CreateGroup("group", stream.MinID, true)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("last ID", func(t *testing.T) {
+		addEntries(t, s, 2)
+
+		err := s.CreateGroup("new", stream.LastID, false)
+		assert.Nil(t, err)
+
+		entries, err := s.ReadGroup("new", "consumer", 0)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestRedisStream_DestroyGroup(t *testing.T) {
+	s := newGroup(t, 1)
+	defer s.Base().Delete()
+
+	value, err := s.DestroyGroup("group")
+	assert.Nil(t, err)
+	assert.True(t, value)
+
+	value, err = s.DestroyGroup("group")
+	assert.Nil(t, err)
+	assert.False(t, value)
+}
+
+func TestRedisStream_Pending(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	t.Run("no pending entries", func(t *testing.T) {
+		summary, err := s.Pending("group")
+		assert.Nil(t, err)
+		assert.Equal(t, stream.PendingSummary{}, summary)
+	})
+
+	t.Run("pending entries", func(t *testing.T) {
+		_, err := s.ReadGroup("group", "consumer1", 2)
+		assert.Nil(t, err)
+		_, err = s.ReadGroup("group", "consumer2", 0)
+		assert.Nil(t, err)
+
+		summary, err := s.Pending("group")
+		assert.Nil(t, err)
+		assert.Equal(t, stream.PendingSummary{
+			Count:     3,
+			Lowest:    stream.ID{Millis: 1},
+			Highest:   stream.ID{Millis: 3},
+			Consumers: map[string]int64{"consumer1": 2, "consumer2": 1},
+		}, summary)
+	})
+}
+
+func TestRedisStream_PendingEntries(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	_, err := s.ReadGroup("group", "consumer1", 2)
+	assert.Nil(t, err)
+	_, err = s.ReadGroup("group", "consumer2", 0)
+	assert.Nil(t, err)
+
+	t.Run("all", func(t *testing.T) {
+		entries, err := s.PendingEntries("group", stream.MinID, stream.MaxID, 10, stream.PendingOptions{})
+		assert.Nil(t, err)
+		if assert.Len(t, entries, 3) {
+			assert.Equal(t, stream.ID{Millis: 1}, entries[0].ID)
+			assert.Equal(t, "consumer1", entries[0].Consumer)
+			assert.EqualValues(t, 1, entries[0].Deliveries)
+			assert.Equal(t, "consumer2", entries[2].Consumer)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		entries, err := s.PendingEntries("group", stream.ID{Millis: 2}, stream.MaxID, 1, stream.PendingOptions{})
+		assert.Nil(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, stream.ID{Millis: 2}, entries[0].ID)
+		}
+	})
+
+	t.Run("consumer", func(t *testing.T) {
+		entries, err := s.PendingEntries("group", stream.MinID, stream.MaxID, 10, stream.PendingOptions{Consumer: "consumer2"})
+		assert.Nil(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, stream.ID{Millis: 3}, entries[0].ID)
+		}
+	})
+
+	t.Run("min idle", func(t *testing.T) {
+		entries, err := s.PendingEntries("group", stream.MinID, stream.MaxID, 10, stream.PendingOptions{MinIdle: time.Minute})
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestRedisStream_ReadGroup(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	entries, err := s.ReadGroup("group", "consumer", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []stream.ID{{Millis: 1}, {Millis: 2}}, ids(entries))
+
+	entries, err = s.ReadGroup("group", "consumer", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []stream.ID{{Millis: 3}}, ids(entries))
+
+	entries, err = s.ReadGroup("group", "consumer", 0)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRedisStream_SetGroupID(t *testing.T) {
+	s := newGroup(t, 3)
+	defer s.Base().Delete()
+
+	err := s.SetGroupID("group", stream.ID{Millis: 2})
+	assert.Nil(t, err)
+
+	entries, err := s.ReadGroup("group", "consumer", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []stream.ID{{Millis: 3}}, ids(entries))
+}
@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// ID is the ID of an entry in a stream. It is made up of a Unix time in milliseconds
+// and a sequence number for entries added in the same millisecond. IDs can be compared
+// with == and ordered with Less.
+type ID struct {
+	Millis uint64
+	Seq    uint64
+
+	// special is the string sent for IDs that don't have a value, such as LastID.
+	special string
+}
+
+var (
+	// MinID is the lowest possible ID. It can be used as the start of a range to
+	// include the first entry.
+	MinID = ID{}
+
+	// MaxID is the highest possible ID. It can be used as the end of a range to
+	// include the last entry.
+	MaxID = ID{Millis: math.MaxUint64, Seq: math.MaxUint64}
+
+	// LastID stands for the ID of the last entry in the stream at the time a command
+	// runs, like $ in the Redis documentation. It can be used with BlockingRead to
+	// wait for new entries, and when creating a consumer group to only deliver new
+	// entries.
+	LastID = ID{special: "$"}
+)
+
+// ParseID parses an ID in the format used by Redis, <millis>-<seq>. The sequence number
+// can be left out, in which case it is 0.
+func ParseID(s string) (ID, error) {
+	millis, seq := s, "0"
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		millis, seq = s[:i], s[i+1:]
+	}
+
+	var id ID
+	var err error
+	if id.Millis, err = strconv.ParseUint(millis, 10, 64); err != nil {
+		return ID{}, errors.New("Invalid stream ID")
+	}
+	if id.Seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+		return ID{}, errors.New("Invalid stream ID")
+	}
+	return id, nil
+}
+
+// String returns the ID in the format used by Redis.
+func (id ID) String() string {
+	if id.special != "" {
+		return id.special
+	}
+	return strconv.FormatUint(id.Millis, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+// Less returns whether id comes before other in a stream.
+func (id ID) Less(other ID) bool {
+	if id.Millis != other.Millis {
+		return id.Millis < other.Millis
+	}
+	return id.Seq < other.Seq
+}
+
+// Next returns the lowest ID that comes after id. It can be used to start a range
+// right after an entry that was already read.
+func (id ID) Next() ID {
+	if id.Seq == math.MaxUint64 {
+		return ID{Millis: id.Millis + 1}
+	}
+	return ID{Millis: id.Millis, Seq: id.Seq + 1}
+}
+
+// parseIDReply converts a reply holding an ID.
+func parseIDReply(reply interface{}, err error) (ID, error) {
+	s, err := redis.String(reply, err)
+	if err != nil {
+		return ID{}, err
+	}
+	return ParseID(s)
+}
@@ -0,0 +1,54 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		s     string
+		id    stream.ID
+		valid bool
+	}{
+		{"1526919030474-55", stream.ID{Millis: 1526919030474, Seq: 55}, true},
+		{"1526919030474", stream.ID{Millis: 1526919030474}, true},
+		{"0-0", stream.MinID, true},
+		{"18446744073709551615-18446744073709551615", stream.MaxID, true},
+		{"", stream.ID{}, false},
+		{"abc-1", stream.ID{}, false},
+		{"1-abc", stream.ID{}, false},
+		{"1-", stream.ID{}, false},
+		{"-1", stream.ID{}, false},
+	}
+
+	for _, test := range tests {
+		id, err := stream.ParseID(test.s)
+		if test.valid {
+			assert.Nil(t, err, test.s)
+			assert.Equal(t, test.id, id, test.s)
+		} else {
+			assert.NotNil(t, err, test.s)
+		}
+	}
+}
+
+func TestID_String(t *testing.T) {
+	assert.Equal(t, "1526919030474-55", stream.ID{Millis: 1526919030474, Seq: 55}.String())
+	assert.Equal(t, "0-0", stream.MinID.String())
+	assert.Equal(t, "$", stream.LastID.String())
+}
+
+func TestID_Less(t *testing.T) {
+	assert.True(t, stream.ID{Millis: 1, Seq: 5}.Less(stream.ID{Millis: 2}))
+	assert.True(t, stream.ID{Millis: 1, Seq: 5}.Less(stream.ID{Millis: 1, Seq: 6}))
+	assert.False(t, stream.ID{Millis: 1, Seq: 5}.Less(stream.ID{Millis: 1, Seq: 5}))
+	assert.False(t, stream.MaxID.Less(stream.MinID))
+}
+
+func TestID_Next(t *testing.T) {
+	assert.Equal(t, stream.ID{Millis: 1, Seq: 6}, stream.ID{Millis: 1, Seq: 5}.Next())
+	assert.Equal(t, stream.ID{Millis: 2}, stream.ID{Millis: 1, Seq: 18446744073709551615}.Next())
+}
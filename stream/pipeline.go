@@ -0,0 +1,70 @@
+package stream
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+)
+
+// IDFuture is a Future for a reply holding a stream ID.
+type IDFuture struct {
+	*redistypes.Future
+}
+
+// Result returns the reply to the command as an ID.
+func (f IDFuture) Result() (ID, error) {
+	return parseIDReply(f.Reply())
+}
+
+// Pipelined is a Stream whose commands are queued in a redistypes.Batch instead of being
+// sent immediately. Each method returns a future that is resolved when the Batch is
+// executed. The methods work like the Stream methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Add queues the Redis command XADD. See Stream.Add. If options.NoMkStream is set
+	// and the stream doesn't exist, the future returns redis.ErrNil.
+	Add(fields map[string]interface{}, options AddOptions) IDFuture
+
+	// Delete queues the Redis command XDEL. See Stream.Delete.
+	Delete(ids ...ID) redistypes.Uint64Future
+
+	// Length queues the Redis command XLEN. See Stream.Length.
+	Length() redistypes.Uint64Future
+
+	// Trim queues the Redis command XTRIM. See Stream.Trim.
+	Trim(options TrimOptions) redistypes.Uint64Future
+}
+
+type pipelinedStream struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined stream that queues the commands of s in b.
+func NewPipelined(b redistypes.Batch, s Stream) Pipelined {
+	return &pipelinedStream{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, s.Base()),
+	}
+}
+
+func (r pipelinedStream) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedStream) Add(fields map[string]interface{}, options AddOptions) IDFuture {
+	return IDFuture{Future: r.batch.Queue("XADD", addArgs(r.base.Name(), fields, options)...)}
+}
+
+func (r *pipelinedStream) Delete(ids ...ID) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("XDEL", idArgs(r.base.Name(), ids)...)}
+}
+
+func (r *pipelinedStream) Length() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("XLEN", r.base.Name())}
+}
+
+func (r *pipelinedStream) Trim(options TrimOptions) redistypes.Uint64Future {
+	args := append([]interface{}{r.base.Name()}, trimArgs(options)...)
+	return redistypes.Uint64Future{Future: r.batch.Queue("XTRIM", args...)}
+}
@@ -0,0 +1,53 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	ps := stream.NewPipelined(p, s)
+	assert.Equal(t, s.Base().Name(), ps.Base().Name())
+
+	first := ps.Add(map[string]interface{}{"a": 1}, stream.AddOptions{ID: stream.ID{Millis: 1}})
+	second := ps.Add(map[string]interface{}{"a": 2}, stream.AddOptions{})
+	third := ps.Add(map[string]interface{}{"a": 3}, stream.AddOptions{ID: stream.ID{Millis: 1}})
+	del := ps.Delete(stream.ID{Millis: 1})
+	trim := ps.Trim(stream.TrimOptions{Strategy: stream.TrimMaxLen, MaxLen: 0})
+	length := ps.Length()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	id, err := first.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, stream.ID{Millis: 1}, id)
+
+	id, err = second.Result()
+	assert.Nil(t, err)
+	assert.True(t, stream.ID{Millis: 1}.Less(id))
+
+	_, err = third.Result()
+	assert.NotNil(t, err)
+
+	count, err := del.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	count, err = trim.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, count)
+
+	count, err = length.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, count)
+}
@@ -0,0 +1,552 @@
+// Package stream contains a Go implementation of the stream data structure in Redis, which is an
+// append-only log of entries made up of fields and values. For more information about how the
+// data structure works, see the Redis documentation.
+package stream
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// Entry is an entry in a stream.
+type Entry struct {
+	ID     ID
+	Fields map[string]string
+}
+
+// TrimStrategy is the way entries are selected for trimming.
+type TrimStrategy string
+
+const (
+	// TrimMaxLen removes the oldest entries until the stream has at most MaxLen
+	// entries.
+	TrimMaxLen TrimStrategy = "MAXLEN"
+
+	// TrimMinID removes the entries with IDs lower than MinID.
+	TrimMinID TrimStrategy = "MINID"
+)
+
+// TrimOptions are the options for trimming a stream with the Redis commands XTRIM
+// and XADD.
+type TrimOptions struct {
+	// Strategy selects the entries that are removed. If it is empty, the stream
+	// isn't trimmed.
+	Strategy TrimStrategy
+
+	// MaxLen is the number of entries kept with TrimMaxLen.
+	MaxLen int64
+
+	// MinID is the lowest ID kept with TrimMinID.
+	MinID ID
+
+	// Approximate lets Redis trim the stream only when it can remove a whole node,
+	// which is much more efficient. The stream may then be slightly longer than
+	// requested.
+	Approximate bool
+
+	// Limit is the highest number of entries removed when Approximate is set, or 0
+	// for the default.
+	Limit int64
+}
+
+// AddOptions are the options for the Redis command XADD.
+type AddOptions struct {
+	// ID is the ID of the new entry. If it is the zero ID, Redis generates it.
+	ID ID
+
+	// NoMkStream doesn't create the stream if it doesn't exist.
+	NoMkStream bool
+
+	// Trim trims the stream after the entry is added.
+	Trim TrimOptions
+}
+
+// Info is the information about a stream returned by the Redis command XINFO STREAM.
+type Info struct {
+	Length               int64
+	RadixTreeKeys        int64
+	RadixTreeNodes       int64
+	Groups               int64
+	LastGeneratedID      ID
+	MaxDeletedEntryID    ID
+	RecordedFirstEntryID ID
+	EntriesAdded         int64
+	FirstEntry           *Entry
+	LastEntry            *Entry
+}
+
+// Stream is a Redis implementation of a stream.
+type Stream interface {
+	// Base returns the base Type.
+	Base() redistypes.Type
+
+	// Ack implements the Redis command XACK. It acknowledges that the entries with the
+	// given IDs were processed by a consumer in group, which removes them from the
+	// pending entries of the group. It returns the number of entries acknowledged.
+	//
+	// See https://redis.io/commands/xack.
+	Ack(group string, ids ...ID) (uint64, error)
+
+	// Add implements the Redis command XADD. It adds an entry with fields to the
+	// stream and returns its ID. If options.NoMkStream is set and the stream
+	// doesn't exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/xadd.
+	Add(fields map[string]interface{}, options AddOptions) (ID, error)
+
+	// AutoClaim implements the Redis command XAUTOCLAIM. It transfers up to count
+	// entries pending in group that have been idle for at least minIdle to consumer,
+	// starting at the ID start, and returns them. It also returns the ID to use as
+	// start in the next call, which is MinID when all of the pending entries have
+	// been scanned.
+	//
+	// See https://redis.io/commands/xautoclaim.
+	AutoClaim(group, consumer string, minIdle time.Duration, start ID, count int64) (ID, []Entry, error)
+
+	// BlockingRead implements the Redis command XREAD with the BLOCK option. It works
+	// like Read, but if there are no entries after the ID after, it blocks until an
+	// entry is added or timeout is reached. If the timeout is reached, no entries are
+	// returned. A timeout of 0 can be used to block indefinitely. LastID can be used
+	// as after to only return entries added after BlockingRead is called.
+	//
+	// Since the timeout is sent in seconds, millisecond-level precision is not
+	// possible. If the timeout is not a multiple of one second, an error will be
+	// returned.
+	//
+	// See https://redis.io/commands/xread.
+	BlockingRead(after ID, count int64, timeout time.Duration) ([]Entry, error)
+
+	// BlockingReadGroup implements the Redis command XREADGROUP with the BLOCK option.
+	// It works like ReadGroup, but if there are no new entries, it blocks until an entry
+	// is added or timeout is reached, in the same way as BlockingRead.
+	//
+	// See https://redis.io/commands/xreadgroup.
+	BlockingReadGroup(group, consumer string, count int64, timeout time.Duration) ([]Entry, error)
+
+	// Claim implements the Redis command XCLAIM. It transfers the pending entries with
+	// the given IDs that have been idle for at least minIdle to consumer in group, and
+	// returns them. Entries that were deleted from the stream are returned with nil
+	// Fields by some versions of Redis, and left out by others.
+	//
+	// See https://redis.io/commands/xclaim.
+	Claim(group, consumer string, minIdle time.Duration, ids ...ID) ([]Entry, error)
+
+	// CloneTo copies the stream to a new key called name using the Redis command COPY,
+	// and returns a Stream for the copy. If name already exists, it is overwritten. If
+	// the stream does not exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (Stream, error)
+
+	// Consume reads the entries of the stream as consumer in group and passes each one
+	// to handler, until ctx is done or a command fails. An entry is acknowledged when
+	// handler returns nil. Otherwise it stays pending, and is claimed again once it has
+	// been idle for options.MinIdle, either by this consumer or by another one in the
+	// group. See ConsumeOptions for the concurrency and dead-letter settings.
+	//
+	// Consume only uses the connection of the stream from one goroutine, so it can be
+	// used with a single connection. When ctx is done, Consume waits for the handlers
+	// that are running, acknowledges the entries they processed and returns ctx.Err().
+	Consume(ctx context.Context, group, consumer string, handler Handler, options ConsumeOptions) error
+
+	// CreateGroup implements the Redis command XGROUP CREATE. It creates a consumer
+	// group that delivers the entries after the ID start, or only new entries if start
+	// is LastID. If mkStream is true, the stream is created if it doesn't exist.
+	// Otherwise an error is returned. If the group already exists, an error is returned.
+	//
+	// See https://redis.io/commands/xgroup-create.
+	CreateGroup(group string, start ID, mkStream bool) error
+
+	// Delete implements the Redis command XDEL. It removes the entries with the given
+	// IDs and returns the number removed.
+	//
+	// See https://redis.io/commands/xdel.
+	Delete(ids ...ID) (uint64, error)
+
+	// DestroyGroup implements the Redis command XGROUP DESTROY. It deletes the consumer
+	// group, including its pending entries, and returns whether it existed.
+	//
+	// See https://redis.io/commands/xgroup-destroy.
+	DestroyGroup(group string) (bool, error)
+
+	// Info implements the Redis command XINFO STREAM. It returns information about the
+	// stream. If the stream doesn't exist, an error is returned.
+	//
+	// See https://redis.io/commands/xinfo-stream.
+	Info() (Info, error)
+
+	// Length implements the Redis command XLEN. It returns the number of entries in
+	// the stream, or 0 if it doesn't exist.
+	//
+	// See https://redis.io/commands/xlen.
+	Length() (uint64, error)
+
+	// Pending implements the Redis command XPENDING. It returns a summary of the
+	// entries pending in group.
+	//
+	// See https://redis.io/commands/xpending.
+	Pending(group string) (PendingSummary, error)
+
+	// PendingEntries implements the Redis command XPENDING with a range. It returns up
+	// to count of the entries pending in group with IDs from start to end, filtered
+	// according to options.
+	//
+	// See https://redis.io/commands/xpending.
+	PendingEntries(group string, start, end ID, count int64, options PendingOptions) ([]PendingEntry, error)
+
+	// Range implements the Redis command XRANGE. It returns the entries with IDs from
+	// start to end, inclusive, starting with the oldest. If count is greater than 0, at
+	// most count entries are returned. MinID and MaxID can be used for an open range,
+	// and ID.Next for an exclusive start.
+	//
+	// See https://redis.io/commands/xrange.
+	Range(start, end ID, count int64) ([]Entry, error)
+
+	// Read implements the Redis command XREAD. It returns the entries with IDs greater
+	// than after, starting with the oldest. If count is greater than 0, at most count
+	// entries are returned.
+	//
+	// See https://redis.io/commands/xread.
+	Read(after ID, count int64) ([]Entry, error)
+
+	// ReadGroup implements the Redis command XREADGROUP. It returns the entries that
+	// were never delivered to a consumer in group, and delivers them to consumer. The
+	// entries are pending until they are acknowledged with Ack. If count is greater
+	// than 0, at most count entries are returned.
+	//
+	// See https://redis.io/commands/xreadgroup.
+	ReadGroup(group, consumer string, count int64) ([]Entry, error)
+
+	// RevRange implements the Redis command XREVRANGE. It works like Range, but it
+	// returns the entries from end to start, starting with the newest.
+	//
+	// See https://redis.io/commands/xrevrange.
+	RevRange(end, start ID, count int64) ([]Entry, error)
+
+	// SetGroupID implements the Redis command XGROUP SETID. It sets the ID of the last
+	// entry delivered by group, so the next entries delivered are the ones after id.
+	//
+	// See https://redis.io/commands/xgroup-setid.
+	SetGroupID(group string, id ID) error
+
+	// Trim implements the Redis command XTRIM. It removes entries from the stream
+	// according to options and returns the number removed.
+	//
+	// See https://redis.io/commands/xtrim.
+	Trim(options TrimOptions) (uint64, error)
+
+	// WithContext returns a copy of the Stream that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) Stream
+}
+
+type redisStream struct {
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
+}
+
+// NewRedisStream creates a Redis implementation of Stream given redigo connection conn and
+// name. The Redis key used to identify the Stream will be name.
+func NewRedisStream(conn redis.Conn, name string) Stream {
+	return NewRedisStreamFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisStreamFromProvider creates a Redis implementation of Stream given ConnProvider p
+// and name. Each command borrows a connection from p. The Redis key used to identify the
+// Stream will be name.
+func NewRedisStreamFromProvider(p redistypes.ConnProvider, name string) Stream {
+	return &redisStream{
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
+	}
+}
+
+func (r redisStream) Base() redistypes.Type {
+	return r.base
+}
+
+func (r *redisStream) Add(fields map[string]interface{}, options AddOptions) (ID, error) {
+	id, err := parseIDReply(internal.Do(r.ctx, r.provider, "XADD", addArgs(r.Base().Name(), fields, options)...))
+	if err == redis.ErrNil {
+		return ID{}, redistypes.ErrKeyNotFound
+	}
+	return id, err
+}
+
+func (r *redisStream) BlockingRead(after ID, count int64, timeout time.Duration) ([]Entry, error) {
+	seconds := int64(timeout.Seconds())
+	if timeout.Nanoseconds()-seconds*time.Second.Nanoseconds() != 0 {
+		return nil, errors.New("Duration is not a multiple of one second")
+	}
+
+	// The command may be sent several times, so $ is resolved first to make sure
+	// entries added in between aren't missed.
+	if after == LastID {
+		last, err := r.RevRange(MaxID, MinID, 1)
+		if err != nil {
+			return nil, err
+		} else if len(last) > 0 {
+			after = last[0].ID
+		} else {
+			after = MinID
+		}
+	}
+
+	return readReply(internal.BlockingDo(r.ctx, r.provider, timeout, "XREAD", func(seconds int64) []interface{} {
+		return readArgs(r.Base().Name(), after, count, seconds*1000)
+	}))
+}
+
+func (r *redisStream) CloneTo(name string) (Stream, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisStreamFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisStream) Delete(ids ...ID) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "XDEL", idArgs(r.Base().Name(), ids)...))
+}
+
+func (r *redisStream) Info() (Info, error) {
+	values, err := redis.Values(internal.Do(r.ctx, r.provider, "XINFO", "STREAM", r.Base().Name()))
+	if err != nil {
+		return Info{}, err
+	} else if len(values)%2 != 0 {
+		return Info{}, errors.New("Unexpected response length")
+	}
+
+	var info Info
+	for i := 0; i < len(values); i += 2 {
+		name, err := redis.String(values[i], nil)
+		if err != nil {
+			return Info{}, err
+		}
+
+		value := values[i+1]
+		switch name {
+		case "length":
+			info.Length, err = redis.Int64(value, nil)
+		case "radix-tree-keys":
+			info.RadixTreeKeys, err = redis.Int64(value, nil)
+		case "radix-tree-nodes":
+			info.RadixTreeNodes, err = redis.Int64(value, nil)
+		case "groups":
+			info.Groups, err = redis.Int64(value, nil)
+		case "entries-added":
+			info.EntriesAdded, err = redis.Int64(value, nil)
+		case "last-generated-id":
+			info.LastGeneratedID, err = parseIDReply(value, nil)
+		case "max-deleted-entry-id":
+			info.MaxDeletedEntryID, err = parseIDReply(value, nil)
+		case "recorded-first-entry-id":
+			info.RecordedFirstEntryID, err = parseIDReply(value, nil)
+		case "first-entry":
+			info.FirstEntry, err = entryPointer(value)
+		case "last-entry":
+			info.LastEntry, err = entryPointer(value)
+		}
+		if err != nil {
+			return Info{}, err
+		}
+	}
+	return info, nil
+}
+
+func (r *redisStream) Length() (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "XLEN", r.Base().Name()))
+}
+
+func (r *redisStream) Range(start, end ID, count int64) ([]Entry, error) {
+	return entrySlice(internal.Do(r.ctx, r.provider, "XRANGE", rangeArgs(r.Base().Name(), start, end, count)...))
+}
+
+func (r *redisStream) Read(after ID, count int64) ([]Entry, error) {
+	return readReply(internal.Do(r.ctx, r.provider, "XREAD", readArgs(r.Base().Name(), after, count, -1)...))
+}
+
+func (r *redisStream) RevRange(end, start ID, count int64) ([]Entry, error) {
+	return entrySlice(internal.Do(r.ctx, r.provider, "XREVRANGE", rangeArgs(r.Base().Name(), end, start, count)...))
+}
+
+func (r *redisStream) Trim(options TrimOptions) (uint64, error) {
+	args := append([]interface{}{r.Base().Name()}, trimArgs(options)...)
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "XTRIM", args...))
+}
+
+func (r *redisStream) WithContext(ctx context.Context) Stream {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisStream{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// addArgs returns the arguments to XADD.
+func addArgs(name string, fields map[string]interface{}, options AddOptions) []interface{} {
+	args := []interface{}{name}
+	if options.NoMkStream {
+		args = append(args, "NOMKSTREAM")
+	}
+	args = append(args, trimArgs(options.Trim)...)
+	if options.ID == (ID{}) {
+		args = append(args, "*")
+	} else {
+		args = append(args, options.ID.String())
+	}
+	for field, value := range fields {
+		args = append(args, field, value)
+	}
+	return args
+}
+
+// trimArgs returns the trimming arguments to XTRIM or XADD.
+func trimArgs(options TrimOptions) []interface{} {
+	if options.Strategy == "" {
+		return nil
+	}
+
+	args := []interface{}{string(options.Strategy)}
+	if options.Approximate {
+		args = append(args, "~")
+	}
+	if options.Strategy == TrimMinID {
+		args = append(args, options.MinID.String())
+	} else {
+		args = append(args, options.MaxLen)
+	}
+	if options.Approximate && options.Limit > 0 {
+		args = append(args, "LIMIT", options.Limit)
+	}
+	return args
+}
+
+// idArgs returns the arguments to a command that takes name followed by IDs.
+func idArgs(name string, ids []ID) []interface{} {
+	args := make([]interface{}, 0, 1+len(ids))
+	args = append(args, name)
+	for _, id := range ids {
+		args = append(args, id.String())
+	}
+	return args
+}
+
+// rangeArgs returns the arguments to XRANGE or XREVRANGE.
+func rangeArgs(name string, first, last ID, count int64) []interface{} {
+	args := []interface{}{name, first.String(), last.String()}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	return args
+}
+
+// readArgs returns the arguments to XREAD. If block is negative, the BLOCK option
+// isn't sent.
+func readArgs(name string, after ID, count, block int64) []interface{} {
+	var args []interface{}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	if block >= 0 {
+		args = append(args, "BLOCK", block)
+	}
+	return append(args, "STREAMS", name, after.String())
+}
+
+// readReply converts the reply to XREAD or XREADGROUP for a single stream.
+func readReply(reply interface{}, err error) ([]Entry, error) {
+	streams, err := redis.Values(reply, err)
+	if err == redis.ErrNil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else if len(streams) != 1 {
+		return nil, errors.New("Unexpected response length")
+	}
+
+	stream, err := redis.Values(streams[0], nil)
+	if err != nil {
+		return nil, err
+	} else if len(stream) != 2 {
+		return nil, errors.New("Unexpected response length")
+	}
+	return entrySlice(stream[1], nil)
+}
+
+// entrySlice converts a reply holding a list of entries.
+func entrySlice(reply interface{}, err error) ([]Entry, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		// Some versions of Redis return nil for entries that were deleted.
+		if value == nil {
+			continue
+		}
+
+		e, err := entry(value)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// entryPointer converts a reply holding an entry that may be nil.
+func entryPointer(reply interface{}) (*Entry, error) {
+	if reply == nil {
+		return nil, nil
+	}
+
+	e, err := entry(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// entry converts a reply holding an entry, which is made up of its ID and a list of
+// fields and values. The list is nil for entries that were deleted but are still
+// pending in a consumer group.
+func entry(reply interface{}) (Entry, error) {
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return Entry{}, err
+	} else if len(values) != 2 {
+		return Entry{}, errors.New("Unexpected response length")
+	}
+
+	id, err := parseIDReply(values[0], nil)
+	if err != nil {
+		return Entry{}, err
+	}
+	if values[1] == nil {
+		return Entry{ID: id}, nil
+	}
+
+	fields, err := redis.StringMap(values[1], nil)
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{ID: id, Fields: fields}, nil
+}
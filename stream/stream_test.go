@@ -0,0 +1,377 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/stream"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func ExampleNewRedisStream() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	events := stream.NewRedisStream(conn, "my_events")
+	defer events.Base().Delete()
+
+	_, _ = events.Add(map[string]interface{}{"type": "login", "user": "alice"}, stream.AddOptions{})
+	_, _ = events.Add(map[string]interface{}{"type": "logout", "user": "alice"}, stream.AddOptions{})
+
+	entries, _ := events.Range(stream.MinID, stream.MaxID, 0)
+	for _, entry := range entries {
+		fmt.Println(entry.Fields["type"], entry.Fields["user"])
+	}
+
+	// Output:
+	// login alice
+	// logout alice
+}
+
+// addEntries adds entries with IDs 1-0 to n-0 to s, each with a field n.
+func addEntries(t *testing.T, s stream.Stream, n int) {
+	for i := 1; i <= n; i++ {
+		_, err := s.Add(map[string]interface{}{"n": i}, stream.AddOptions{ID: stream.ID{Millis: uint64(i)}})
+		assert.Nil(t, err)
+	}
+}
+
+// ids returns the IDs of entries.
+func ids(entries []stream.Entry) []stream.ID {
+	result := make([]stream.ID, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.ID
+	}
+	return result
+}
+
+func TestNewRedisStreamFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	s := stream.NewRedisStreamFromProvider(pool, test.RandomKey())
+	defer s.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.Add(map[string]interface{}{"i": i}, stream.AddOptions{})
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	value, err := s.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
+func TestRedisStream_Add(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("NoMkStream", func(t *testing.T) {
+		_, err := s.Add(map[string]interface{}{"a": 1}, stream.AddOptions{NoMkStream: true})
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("explicit ID", func(t *testing.T) {
+		id, err := s.Add(map[string]interface{}{"a": 1}, stream.AddOptions{ID: stream.ID{Millis: 5, Seq: 1}})
+		assert.Nil(t, err)
+		assert.Equal(t, stream.ID{Millis: 5, Seq: 1}, id)
+
+		_, err = s.Add(map[string]interface{}{"a": 1}, stream.AddOptions{ID: stream.ID{Millis: 5, Seq: 1}})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("generated ID", func(t *testing.T) {
+		id, err := s.Add(map[string]interface{}{"a": 2, "b": "x"}, stream.AddOptions{})
+		assert.Nil(t, err)
+		assert.True(t, stream.ID{Millis: 5, Seq: 1}.Less(id))
+
+		entries, err := s.Range(id, id, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.Entry{{ID: id, Fields: map[string]string{"a": "2", "b": "x"}}}, entries)
+	})
+
+	t.Run("MaxLen", func(t *testing.T) {
+		_, err := s.Add(map[string]interface{}{"a": 3}, stream.AddOptions{
+			Trim: stream.TrimOptions{Strategy: stream.TrimMaxLen, MaxLen: 1},
+		})
+		assert.Nil(t, err)
+
+		value, err := s.Length()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, value)
+	})
+
+	t.Run("MinID", func(t *testing.T) {
+		_, _ = s.Base().Delete()
+		addEntries(t, s, 3)
+
+		_, err := s.Add(map[string]interface{}{"a": 4}, stream.AddOptions{
+			ID:   stream.ID{Millis: 4},
+			Trim: stream.TrimOptions{Strategy: stream.TrimMinID, MinID: stream.ID{Millis: 3}},
+		})
+		assert.Nil(t, err)
+
+		entries, err := s.Range(stream.MinID, stream.MaxID, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 3}, {Millis: 4}}, ids(entries))
+	})
+}
+
+func TestRedisStream_BlockingRead(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := s.BlockingRead(stream.MinID, 0, time.Millisecond)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		entries, err := s.BlockingRead(stream.LastID, 0, time.Second)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("existing entries", func(t *testing.T) {
+		addEntries(t, s, 3)
+
+		entries, err := s.BlockingRead(stream.ID{Millis: 1}, 1, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 2}}, ids(entries))
+	})
+
+	t.Run("new entry", func(t *testing.T) {
+		pool := &redis.Pool{
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", internal.GetHostAndPort())
+			},
+		}
+		defer pool.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			_, _ = stream.NewRedisStreamFromProvider(pool, s.Base().Name()).Add(
+				map[string]interface{}{"n": 4}, stream.AddOptions{ID: stream.ID{Millis: 4}})
+		}()
+
+		entries, err := stream.NewRedisStreamFromProvider(pool, s.Base().Name()).WithContext(ctx).
+			BlockingRead(stream.LastID, 0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 4}}, ids(entries))
+	})
+}
+
+func TestRedisStream_CloneTo(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := s.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		addEntries(t, s, 2)
+
+		clone, err := s.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = s.Add(map[string]interface{}{"a": 1}, stream.AddOptions{})
+
+		value, err := clone.Length()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+}
+
+func TestRedisStream_Delete(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	addEntries(t, s, 3)
+
+	value, err := s.Delete(stream.ID{Millis: 1}, stream.ID{Millis: 3}, stream.ID{Millis: 5})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+
+	entries, err := s.Range(stream.MinID, stream.MaxID, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []stream.ID{{Millis: 2}}, ids(entries))
+}
+
+func TestRedisStream_Info(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := s.Info()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		addEntries(t, s, 3)
+
+		info, err := s.Info()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, info.Length)
+		assert.EqualValues(t, 0, info.Groups)
+		assert.Equal(t, stream.ID{Millis: 3}, info.LastGeneratedID)
+		assert.Equal(t, &stream.Entry{ID: stream.ID{Millis: 1}, Fields: map[string]string{"n": "1"}}, info.FirstEntry)
+		assert.Equal(t, &stream.Entry{ID: stream.ID{Millis: 3}, Fields: map[string]string{"n": "3"}}, info.LastEntry)
+	})
+}
+
+func TestRedisStream_Length(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	value, err := s.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	addEntries(t, s, 2)
+
+	value, err = s.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, value)
+}
+
+func TestRedisStream_Range(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		entries, err := s.Range(stream.MinID, stream.MaxID, 0)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+
+	addEntries(t, s, 5)
+
+	t.Run("all", func(t *testing.T) {
+		entries, err := s.Range(stream.MinID, stream.MaxID, 0)
+		assert.Nil(t, err)
+		assert.Len(t, entries, 5)
+		assert.Equal(t, map[string]string{"n": "1"}, entries[0].Fields)
+	})
+
+	t.Run("exclusive with count", func(t *testing.T) {
+		entries, err := s.Range(stream.ID{Millis: 2}.Next(), stream.MaxID, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 3}, {Millis: 4}}, ids(entries))
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		entries, err := s.RevRange(stream.ID{Millis: 4}, stream.ID{Millis: 2}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 4}, {Millis: 3}, {Millis: 2}}, ids(entries))
+	})
+}
+
+func TestRedisStream_Read(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		entries, err := s.Read(stream.MinID, 0)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		addEntries(t, s, 3)
+
+		entries, err := s.Read(stream.ID{Millis: 1}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 2}, {Millis: 3}}, ids(entries))
+
+		entries, err = s.Read(stream.MinID, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []stream.ID{{Millis: 1}}, ids(entries))
+
+		entries, err = s.Read(stream.ID{Millis: 3}, 0)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestRedisStream_Trim(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	addEntries(t, s, 5)
+
+	t.Run("MaxLen", func(t *testing.T) {
+		value, err := s.Trim(stream.TrimOptions{Strategy: stream.TrimMaxLen, MaxLen: 3})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+
+	t.Run("MinID", func(t *testing.T) {
+		value, err := s.Trim(stream.TrimOptions{Strategy: stream.TrimMinID, MinID: stream.ID{Millis: 5}})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+
+	t.Run("approximate", func(t *testing.T) {
+		_, err := s.Trim(stream.TrimOptions{Strategy: stream.TrimMaxLen, Approximate: true, Limit: 10})
+		assert.Nil(t, err)
+	})
+}
+
+func TestRedisStream_WithContext(t *testing.T) {
+	s := stream.NewRedisStream(conn, test.RandomKey())
+	defer s.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.WithContext(ctx).Add(map[string]interface{}{"a": 1}, stream.AddOptions{})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = s.WithContext(ctx).Base().Exists()
+	assert.Equal(t, context.Canceled, err)
+
+	value, err := s.Length()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
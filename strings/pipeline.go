@@ -0,0 +1,79 @@
+package strings
+
+import (
+	"github.com/MasterOfBinary/redistypes"
+)
+
+// Pipelined is a Value whose commands are queued in a redistypes.Batch instead of being
+// sent immediately. Each method returns a future that is resolved when the Batch is
+// executed. The methods work like the Value methods with the same names.
+type Pipelined interface {
+	// Base returns the base PipelinedType, which queues its commands in the same Batch.
+	Base() redistypes.PipelinedType
+
+	// Append queues the Redis command APPEND. See Value.Append.
+	Append(value interface{}) redistypes.Uint64Future
+
+	// DecrBy queues the Redis command DECRBY. See Value.DecrBy.
+	DecrBy(decrement int64) redistypes.Int64Future
+
+	// Get queues the Redis command GET. See Value.Get.
+	Get() *redistypes.Future
+
+	// Incr queues the Redis command INCR. See Value.Incr.
+	Incr() redistypes.Int64Future
+
+	// IncrBy queues the Redis command INCRBY. See Value.IncrBy.
+	IncrBy(increment int64) redistypes.Int64Future
+
+	// IncrByFloat queues the Redis command INCRBYFLOAT. See Value.IncrByFloat.
+	IncrByFloat(increment float64) redistypes.Float64Future
+
+	// StrLen queues the Redis command STRLEN. See Value.StrLen.
+	StrLen() redistypes.Uint64Future
+}
+
+type pipelinedValue struct {
+	batch redistypes.Batch
+	base  redistypes.PipelinedType
+}
+
+// NewPipelined creates a Pipelined value that queues the commands of v in b.
+func NewPipelined(b redistypes.Batch, v Value) Pipelined {
+	return &pipelinedValue{
+		batch: b,
+		base:  redistypes.NewPipelinedType(b, v.Base()),
+	}
+}
+
+func (r pipelinedValue) Base() redistypes.PipelinedType {
+	return r.base
+}
+
+func (r *pipelinedValue) Append(value interface{}) redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("APPEND", r.base.Name(), value)}
+}
+
+func (r *pipelinedValue) DecrBy(decrement int64) redistypes.Int64Future {
+	return redistypes.Int64Future{Future: r.batch.Queue("DECRBY", r.base.Name(), decrement)}
+}
+
+func (r *pipelinedValue) Get() *redistypes.Future {
+	return r.batch.Queue("GET", r.base.Name())
+}
+
+func (r *pipelinedValue) Incr() redistypes.Int64Future {
+	return redistypes.Int64Future{Future: r.batch.Queue("INCR", r.base.Name())}
+}
+
+func (r *pipelinedValue) IncrBy(increment int64) redistypes.Int64Future {
+	return redistypes.Int64Future{Future: r.batch.Queue("INCRBY", r.base.Name(), increment)}
+}
+
+func (r *pipelinedValue) IncrByFloat(increment float64) redistypes.Float64Future {
+	return redistypes.Float64Future{Future: r.batch.Queue("INCRBYFLOAT", r.base.Name(), increment)}
+}
+
+func (r *pipelinedValue) StrLen() redistypes.Uint64Future {
+	return redistypes.Uint64Future{Future: r.batch.Queue("STRLEN", r.base.Name())}
+}
@@ -0,0 +1,60 @@
+package strings_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/strings"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelined(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	p := redistypes.NewPipeline(redistypes.SingleConn(conn))
+	pv := strings.NewPipelined(p, v)
+	assert.Equal(t, v.Base().Name(), pv.Base().Name())
+
+	incr := pv.Incr()
+	incrBy := pv.IncrBy(10)
+	decrBy := pv.DecrBy(3)
+	incrByFloat := pv.IncrByFloat(0.5)
+	appendValue := pv.Append("1")
+	get := pv.Get()
+	strLen := pv.StrLen()
+
+	err := p.Exec(context.Background())
+	assert.Nil(t, err)
+
+	value, err := incr.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	value, err = incrBy.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 11, value)
+
+	value, err = decrBy.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8, value)
+
+	f, err := incrByFloat.Result()
+	assert.Nil(t, err)
+	assert.Equal(t, 8.5, f)
+
+	length, err := appendValue.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, length)
+
+	str, err := redis.String(get.Reply())
+	assert.Nil(t, err)
+	assert.Equal(t, "8.51", str)
+
+	length, err = strLen.Result()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, length)
+}
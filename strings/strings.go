@@ -0,0 +1,402 @@
+// Package strings contains a Go implementation of the string data type in Redis, which holds a single
+// value such as text, a serialized object or a counter. For more information about how the data type
+// works, see the Redis documentation.
+package strings
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// SetCondition is a condition for the Redis command SET.
+type SetCondition string
+
+const (
+	// SetNX only sets the value if the key doesn't exist.
+	SetNX SetCondition = "NX"
+
+	// SetXX only sets the value if the key already exists.
+	SetXX SetCondition = "XX"
+)
+
+// SetOptions are the options for the Redis command SET.
+type SetOptions struct {
+	// TTL is the time to live of the key. If it is 0, the key doesn't expire. It is
+	// sent with the EX option if it is a multiple of one second, or with the PX
+	// option if it is a multiple of one millisecond. Otherwise an error is returned.
+	TTL time.Duration
+
+	// KeepTTL keeps the time to live of the key if it already exists. It can't be
+	// used with TTL.
+	KeepTTL bool
+
+	// Condition is a condition for setting the value, or empty to always set it.
+	Condition SetCondition
+}
+
+// GetExOptions are the options for the Redis command GETEX. At most one of the
+// options can be set. If none are set, GETEX works like GET.
+type GetExOptions struct {
+	// TTL sets the time to live of the key, in the same way as SetOptions.TTL.
+	TTL time.Duration
+
+	// ExpireAt sets the time at which the key expires. It is sent with the EXAT
+	// option if it is a multiple of one second, or with the PXAT option if it is a
+	// multiple of one millisecond. Otherwise an error is returned.
+	ExpireAt time.Time
+
+	// Persist removes the time to live of the key.
+	Persist bool
+}
+
+// Value is a Redis implementation of a string value.
+type Value interface {
+	// Base returns the base Type.
+	Base() redistypes.Type
+
+	// Append implements the Redis command APPEND. It appends value to the end of the
+	// string and returns its new length. If the key doesn't exist, it is created.
+	//
+	// See https://redis.io/commands/append.
+	Append(value interface{}) (uint64, error)
+
+	// BitField returns a bitmap.BitField for building a Redis BITFIELD command on the
+	// value, which packs integer fields into the string.
+	//
+	// See https://redis.io/commands/bitfield.
+	BitField() *bitmap.BitField
+
+	// CloneTo copies the value to a new key called name using the Redis command COPY,
+	// and returns a Value for the copy. If name already exists, it is overwritten. If
+	// the value does not exist, redistypes.ErrKeyNotFound is returned.
+	//
+	// See https://redis.io/commands/copy.
+	CloneTo(name string) (Value, error)
+
+	// DecrBy implements the Redis command DECRBY. It decrements the integer stored in
+	// the key by decrement and returns the new value. If the key doesn't exist, it is
+	// set to 0 before the decrement.
+	//
+	// See https://redis.io/commands/decrby.
+	DecrBy(decrement int64) (int64, error)
+
+	// Get implements the Redis command GET. It returns the value, or nil if the key
+	// doesn't exist.
+	//
+	// See https://redis.io/commands/get.
+	Get() (interface{}, error)
+
+	// GetDel implements the Redis command GETDEL. It works like Get, but it also
+	// deletes the key.
+	//
+	// See https://redis.io/commands/getdel.
+	GetDel() (interface{}, error)
+
+	// GetEx implements the Redis command GETEX. It works like Get, but it also changes
+	// the time to live of the key according to options.
+	//
+	// See https://redis.io/commands/getex.
+	GetEx(options GetExOptions) (interface{}, error)
+
+	// GetRange implements the Redis command GETRANGE. It returns the substring from
+	// index start to end, inclusive. Negative indices start at the end of the string.
+	// If the key doesn't exist, an empty string is returned.
+	//
+	// See https://redis.io/commands/getrange.
+	GetRange(start, end int64) (string, error)
+
+	// Incr implements the Redis command INCR. It increments the integer stored in the
+	// key by one and returns the new value. If the key doesn't exist, it is set to 0
+	// before the increment.
+	//
+	// See https://redis.io/commands/incr.
+	Incr() (int64, error)
+
+	// IncrBy implements the Redis command INCRBY. It works like Incr, but it
+	// increments the integer by increment.
+	//
+	// See https://redis.io/commands/incrby.
+	IncrBy(increment int64) (int64, error)
+
+	// IncrByFloat implements the Redis command INCRBYFLOAT. It increments the floating
+	// point number stored in the key by increment and returns the new value. If the
+	// key doesn't exist, it is set to 0 before the increment.
+	//
+	// See https://redis.io/commands/incrbyfloat.
+	IncrByFloat(increment float64) (float64, error)
+
+	// Set implements the Redis command SET. It sets the value and returns true, or
+	// false if the condition in options was not met.
+	//
+	// See https://redis.io/commands/set.
+	Set(value interface{}, options SetOptions) (bool, error)
+
+	// SetGet implements the Redis command SET with the GET option. It works like Set,
+	// but it returns the old value, or nil if the key didn't exist.
+	//
+	// See https://redis.io/commands/set.
+	SetGet(value interface{}, options SetOptions) (interface{}, error)
+
+	// SetRange implements the Redis command SETRANGE. It overwrites the string starting
+	// at offset with value, and returns the new length of the string. If the string is
+	// shorter than offset, it is padded with zero bytes.
+	//
+	// See https://redis.io/commands/setrange.
+	SetRange(offset int64, value interface{}) (uint64, error)
+
+	// StrLen implements the Redis command STRLEN. It returns the length of the string,
+	// or 0 if the key doesn't exist.
+	//
+	// See https://redis.io/commands/strlen.
+	StrLen() (uint64, error)
+
+	// WithContext returns a copy of the Value that uses ctx for its commands, including
+	// the commands of its base Type. If ctx is done before a command is sent, ctx.Err()
+	// is returned. If ctx has a deadline, it is used as the timeout for the reply.
+	WithContext(ctx context.Context) Value
+}
+
+type redisValue struct {
+	provider redistypes.ConnProvider
+	base     redistypes.Type
+	ctx      context.Context
+}
+
+// NewRedisValue creates a Redis implementation of Value given redigo connection conn and name.
+// The Redis key used to identify the Value will be name.
+func NewRedisValue(conn redis.Conn, name string) Value {
+	return NewRedisValueFromProvider(redistypes.SingleConn(conn), name)
+}
+
+// NewRedisValueFromProvider creates a Redis implementation of Value given ConnProvider p and
+// name. Each command borrows a connection from p. The Redis key used to identify the Value
+// will be name.
+func NewRedisValueFromProvider(p redistypes.ConnProvider, name string) Value {
+	return &redisValue{
+		provider: p,
+		base:     redistypes.NewRedisTypeFromProvider(p, name),
+		ctx:      context.Background(),
+	}
+}
+
+func (r redisValue) Base() redistypes.Type {
+	return r.base
+}
+
+func (r *redisValue) Append(value interface{}) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "APPEND", r.Base().Name(), value))
+}
+
+func (r *redisValue) BitField() *bitmap.BitField {
+	return bitmap.NewRedisBitmapFromProvider(r.provider, r.Base().Name()).WithContext(r.ctx).BitField()
+}
+
+func (r *redisValue) CloneTo(name string) (Value, error) {
+	copied, err := r.base.Copy(name, true)
+	if err != nil {
+		return nil, err
+	} else if !copied {
+		return nil, redistypes.ErrKeyNotFound
+	}
+
+	return NewRedisValueFromProvider(r.provider, name).WithContext(r.ctx), nil
+}
+
+func (r *redisValue) DecrBy(decrement int64) (int64, error) {
+	return redis.Int64(internal.Do(r.ctx, r.provider, "DECRBY", r.Base().Name(), decrement))
+}
+
+func (r *redisValue) Get() (interface{}, error) {
+	return internal.Do(r.ctx, r.provider, "GET", r.Base().Name())
+}
+
+func (r *redisValue) GetDel() (interface{}, error) {
+	return internal.Do(r.ctx, r.provider, "GETDEL", r.Base().Name())
+}
+
+func (r *redisValue) GetEx(options GetExOptions) (interface{}, error) {
+	args := []interface{}{r.Base().Name()}
+	switch {
+	case options.TTL != 0:
+		ttlArgs, err := ttlArgs(options.TTL)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ttlArgs...)
+	case !options.ExpireAt.IsZero():
+		expireAtArgs, err := expireAtArgs(options.ExpireAt)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, expireAtArgs...)
+	case options.Persist:
+		args = append(args, "PERSIST")
+	}
+
+	return internal.Do(r.ctx, r.provider, "GETEX", args...)
+}
+
+func (r *redisValue) GetRange(start, end int64) (string, error) {
+	return redis.String(internal.Do(r.ctx, r.provider, "GETRANGE", r.Base().Name(), start, end))
+}
+
+func (r *redisValue) Incr() (int64, error) {
+	return redis.Int64(internal.Do(r.ctx, r.provider, "INCR", r.Base().Name()))
+}
+
+func (r *redisValue) IncrBy(increment int64) (int64, error) {
+	return redis.Int64(internal.Do(r.ctx, r.provider, "INCRBY", r.Base().Name(), increment))
+}
+
+func (r *redisValue) IncrByFloat(increment float64) (float64, error) {
+	return redis.Float64(internal.Do(r.ctx, r.provider, "INCRBYFLOAT", r.Base().Name(), increment))
+}
+
+func (r *redisValue) Set(value interface{}, options SetOptions) (bool, error) {
+	args, err := setArgs(r.Base().Name(), value, options)
+	if err != nil {
+		return false, err
+	}
+
+	reply, err := internal.Do(r.ctx, r.provider, "SET", args...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+func (r *redisValue) SetGet(value interface{}, options SetOptions) (interface{}, error) {
+	args, err := setArgs(r.Base().Name(), value, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return internal.Do(r.ctx, r.provider, "SET", append(args, "GET")...)
+}
+
+func (r *redisValue) SetRange(offset int64, value interface{}) (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "SETRANGE", r.Base().Name(), offset, value))
+}
+
+func (r *redisValue) StrLen() (uint64, error) {
+	return redis.Uint64(internal.Do(r.ctx, r.provider, "STRLEN", r.Base().Name()))
+}
+
+func (r *redisValue) WithContext(ctx context.Context) Value {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &redisValue{
+		provider: r.provider,
+		base:     r.base.WithContext(ctx),
+		ctx:      ctx,
+	}
+}
+
+// MultiGet implements the Redis command MGET. It borrows a connection from p and returns
+// the contents of values, in the same order. The content of a Value whose key doesn't
+// exist is nil.
+//
+// See https://redis.io/commands/mget.
+func MultiGet(ctx context.Context, p redistypes.ConnProvider, values ...Value) ([]interface{}, error) {
+	if len(values) == 0 {
+		return nil, errors.New("No values given")
+	}
+
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value.Base().Name()
+	}
+
+	return redis.Values(internal.Do(ctx, p, "MGET", args...))
+}
+
+// MultiSet implements the Redis command MSET. It borrows a connection from p and sets
+// each Value in values to the item of contents with the same index, atomically.
+//
+// See https://redis.io/commands/mset.
+func MultiSet(ctx context.Context, p redistypes.ConnProvider, values []Value, contents []interface{}) error {
+	args, err := multiSetArgs(values, contents)
+	if err != nil {
+		return err
+	}
+
+	_, err = internal.Do(ctx, p, "MSET", args...)
+	return err
+}
+
+// MultiSetNX implements the Redis command MSETNX. It works like MultiSet, but it only
+// sets the values if none of the keys exist, and returns whether they were set.
+//
+// See https://redis.io/commands/msetnx.
+func MultiSetNX(ctx context.Context, p redistypes.ConnProvider, values []Value, contents []interface{}) (bool, error) {
+	args, err := multiSetArgs(values, contents)
+	if err != nil {
+		return false, err
+	}
+
+	return redis.Bool(internal.Do(ctx, p, "MSETNX", args...))
+}
+
+// multiSetArgs returns the arguments to MSET or MSETNX.
+func multiSetArgs(values []Value, contents []interface{}) ([]interface{}, error) {
+	if len(values) == 0 {
+		return nil, errors.New("No values given")
+	} else if len(values) != len(contents) {
+		return nil, errors.New("Number of values and contents differ")
+	}
+
+	args := make([]interface{}, 0, 2*len(values))
+	for i, value := range values {
+		args = append(args, value.Base().Name(), contents[i])
+	}
+	return args, nil
+}
+
+// setArgs returns the arguments to SET, not including GET.
+func setArgs(name string, value interface{}, options SetOptions) ([]interface{}, error) {
+	args := []interface{}{name, value}
+	if options.Condition != "" {
+		args = append(args, string(options.Condition))
+	}
+	if options.TTL != 0 {
+		ttlArgs, err := ttlArgs(options.TTL)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ttlArgs...)
+	}
+	if options.KeepTTL {
+		args = append(args, "KEEPTTL")
+	}
+	return args, nil
+}
+
+// expireAtArgs returns the EXAT or PXAT option for t. If t is not a multiple of one
+// millisecond, an error is returned.
+func expireAtArgs(t time.Time) ([]interface{}, error) {
+	if t.Nanosecond() == 0 {
+		return []interface{}{"EXAT", t.Unix()}, nil
+	} else if t.Nanosecond()%int(time.Millisecond) == 0 {
+		return []interface{}{"PXAT", t.UnixNano() / int64(time.Millisecond)}, nil
+	}
+	return nil, errors.New("Time is not a multiple of one millisecond")
+}
+
+// ttlArgs returns the EX or PX option for ttl. If ttl is not a multiple of one
+// millisecond, an error is returned.
+func ttlArgs(ttl time.Duration) ([]interface{}, error) {
+	if ttl%time.Second == 0 {
+		return []interface{}{"EX", int64(ttl / time.Second)}, nil
+	} else if ttl%time.Millisecond == 0 {
+		return []interface{}{"PX", int64(ttl / time.Millisecond)}, nil
+	}
+	return nil, errors.New("Duration is not a multiple of one millisecond")
+}
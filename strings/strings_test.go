@@ -0,0 +1,443 @@
+package strings_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/bitmap"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/MasterOfBinary/redistypes/strings"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var conn redis.Conn
+
+func ExampleNewRedisValue() {
+	conn, _ := redis.Dial("tcp", "localhost:6379")
+	defer conn.Close()
+
+	v := strings.NewRedisValue(conn, "my_value")
+	defer v.Base().Delete()
+
+	_, _ = v.Set("hello", strings.SetOptions{TTL: time.Minute})
+	_, _ = v.Append(" world")
+
+	value, _ := redis.String(v.Get())
+	fmt.Println(value)
+
+	// Output:
+	// hello world
+}
+
+func TestNewRedisValueFromProvider(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle: 4,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", internal.GetHostAndPort())
+		},
+	}
+	defer pool.Close()
+
+	v := strings.NewRedisValueFromProvider(pool, test.RandomKey())
+	defer v.Base().Delete()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Incr()
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, err := redis.Int64(v.Get())
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
+func TestRedisValue_Append(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	value, err := v.Append("abc")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, value)
+
+	value, err = v.Append("de")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, value)
+}
+
+func TestRedisValue_BitField(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	results, err := v.BitField().
+		Set(bitmap.Unsigned(8), bitmap.FieldOffset(0), 'a').
+		IncrBy(bitmap.Unsigned(8), bitmap.FieldOffset(0), 1).
+		Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, []bitmap.FieldResult{{Value: 0}, {Value: 'b'}}, results)
+
+	value, err := redis.String(v.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "b", value)
+}
+
+func TestRedisValue_CloneTo(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		_, err := v.CloneTo(test.RandomKey())
+		assert.Equal(t, redistypes.ErrKeyNotFound, err)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = v.Set("abc", strings.SetOptions{})
+
+		clone, err := v.CloneTo(test.RandomKey())
+		assert.Nil(t, err)
+		defer clone.Base().Delete()
+
+		_, _ = v.Set("def", strings.SetOptions{})
+
+		value, err := redis.String(clone.Get())
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", value)
+	})
+}
+
+func TestRedisValue_Counters(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	value, err := v.Incr()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, value)
+
+	value, err = v.IncrBy(9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+
+	value, err = v.DecrBy(15)
+	assert.Nil(t, err)
+	assert.EqualValues(t, -5, value)
+
+	f, err := v.IncrByFloat(5.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, f)
+
+	_, err = v.Incr()
+	assert.NotNil(t, err)
+}
+
+func TestRedisValue_Get(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	t.Run("non-existing key", func(t *testing.T) {
+		value, err := v.Get()
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		_, _ = v.Set(123, strings.SetOptions{})
+
+		value, err := redis.Int64(v.Get())
+		assert.Nil(t, err)
+		assert.EqualValues(t, 123, value)
+	})
+}
+
+func TestRedisValue_GetDel(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	_, _ = v.Set("abc", strings.SetOptions{})
+
+	value, err := redis.String(v.GetDel())
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", value)
+
+	exists, err := v.Base().Exists()
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisValue_GetEx(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	_, _ = v.Set("abc", strings.SetOptions{})
+
+	t.Run("TTL", func(t *testing.T) {
+		value, err := redis.String(v.GetEx(strings.GetExOptions{TTL: 1500 * time.Millisecond}))
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", value)
+
+		ttl, err := v.Base().PTTL()
+		assert.Nil(t, err)
+		assert.True(t, ttl > 0 && ttl <= 1500*time.Millisecond)
+	})
+
+	t.Run("Persist", func(t *testing.T) {
+		_, err := v.GetEx(strings.GetExOptions{Persist: true})
+		assert.Nil(t, err)
+
+		_, err = v.Base().TTL()
+		assert.Equal(t, redistypes.ErrNoExpiry, err)
+	})
+
+	t.Run("ExpireAt", func(t *testing.T) {
+		at := time.Now().Add(time.Hour).Truncate(time.Second)
+
+		_, err := v.GetEx(strings.GetExOptions{ExpireAt: at})
+		assert.Nil(t, err)
+
+		expireTime, err := v.Base().ExpireTime()
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(expireTime))
+	})
+
+	t.Run("ExpireAt in milliseconds", func(t *testing.T) {
+		at := time.Now().Add(time.Hour).Truncate(time.Second).Add(250 * time.Millisecond)
+
+		_, err := v.GetEx(strings.GetExOptions{ExpireAt: at})
+		assert.Nil(t, err)
+
+		expireTime, err := v.Base().PExpireTime()
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(expireTime))
+	})
+
+	t.Run("invalid TTL", func(t *testing.T) {
+		_, err := v.GetEx(strings.GetExOptions{TTL: time.Microsecond})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid ExpireAt", func(t *testing.T) {
+		at := time.Now().Add(time.Hour).Truncate(time.Millisecond).Add(time.Microsecond)
+
+		_, err := v.GetEx(strings.GetExOptions{ExpireAt: at})
+		assert.NotNil(t, err)
+
+		expireTime, err := v.Base().PExpireTime()
+		assert.Nil(t, err)
+		assert.False(t, at.Truncate(time.Millisecond).Equal(expireTime))
+	})
+}
+
+func TestRedisValue_GetRange(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	value, err := v.GetRange(0, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, "", value)
+
+	_, _ = v.Set("hello world", strings.SetOptions{})
+
+	value, err = v.GetRange(0, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", value)
+
+	value, err = v.GetRange(-5, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, "world", value)
+}
+
+func TestRedisValue_Set(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	t.Run("XX on non-existing key", func(t *testing.T) {
+		ok, err := v.Set("a", strings.SetOptions{Condition: strings.SetXX})
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("NX on non-existing key", func(t *testing.T) {
+		ok, err := v.Set("a", strings.SetOptions{Condition: strings.SetNX, TTL: time.Minute})
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		ttl, err := v.Base().TTL()
+		assert.Nil(t, err)
+		assert.True(t, ttl > 0 && ttl <= time.Minute)
+	})
+
+	t.Run("NX on existing key", func(t *testing.T) {
+		ok, err := v.Set("b", strings.SetOptions{Condition: strings.SetNX})
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("KeepTTL", func(t *testing.T) {
+		ok, err := v.Set("c", strings.SetOptions{KeepTTL: true})
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		_, err = v.Base().TTL()
+		assert.Nil(t, err)
+	})
+
+	t.Run("PX", func(t *testing.T) {
+		ok, err := v.Set("d", strings.SetOptions{TTL: 2500 * time.Millisecond})
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		ttl, err := v.Base().PTTL()
+		assert.Nil(t, err)
+		assert.True(t, ttl > time.Second && ttl <= 2500*time.Millisecond)
+	})
+
+	t.Run("invalid TTL", func(t *testing.T) {
+		_, err := v.Set("e", strings.SetOptions{TTL: time.Microsecond})
+		assert.NotNil(t, err)
+	})
+
+	value, err := redis.String(v.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "d", value)
+}
+
+func TestRedisValue_SetGet(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	value, err := v.SetGet("a", strings.SetOptions{})
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	str, err := redis.String(v.SetGet("b", strings.SetOptions{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "a", str)
+}
+
+func TestRedisValue_SetRange(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	_, _ = v.Set("hello world", strings.SetOptions{})
+
+	value, err := v.SetRange(6, "redis")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 11, value)
+
+	str, err := redis.String(v.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "hello redis", str)
+}
+
+func TestRedisValue_StrLen(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	value, err := v.StrLen()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, value)
+
+	_, _ = v.Set("abcd", strings.SetOptions{})
+
+	value, err = v.StrLen()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, value)
+}
+
+func TestRedisValue_WithContext(t *testing.T) {
+	v := strings.NewRedisValue(conn, test.RandomKey())
+	defer v.Base().Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := v.WithContext(ctx).Incr()
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = strings.MultiGet(ctx, redistypes.SingleConn(conn), v)
+	assert.Equal(t, context.Canceled, err)
+
+	value, err := v.Get()
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+}
+
+func TestMultiGet(t *testing.T) {
+	a := strings.NewRedisValue(conn, test.RandomKey())
+	defer a.Base().Delete()
+
+	b := strings.NewRedisValue(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	_, _ = a.Set("x", strings.SetOptions{})
+
+	values, err := strings.MultiGet(context.Background(), redistypes.SingleConn(conn), a, b)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{[]byte("x"), nil}, values)
+
+	_, err = strings.MultiGet(context.Background(), redistypes.SingleConn(conn))
+	assert.NotNil(t, err)
+}
+
+func TestMultiSet(t *testing.T) {
+	a := strings.NewRedisValue(conn, test.RandomKey())
+	defer a.Base().Delete()
+
+	b := strings.NewRedisValue(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	err := strings.MultiSet(context.Background(), redistypes.SingleConn(conn), []strings.Value{a, b}, []interface{}{1, 2})
+	assert.Nil(t, err)
+
+	values, err := redis.Strings(strings.MultiGet(context.Background(), redistypes.SingleConn(conn), a, b))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, values)
+
+	err = strings.MultiSet(context.Background(), redistypes.SingleConn(conn), []strings.Value{a, b}, []interface{}{1})
+	assert.NotNil(t, err)
+}
+
+func TestMultiSetNX(t *testing.T) {
+	a := strings.NewRedisValue(conn, test.RandomKey())
+	defer a.Base().Delete()
+
+	b := strings.NewRedisValue(conn, test.RandomKey())
+	defer b.Base().Delete()
+
+	ok, err := strings.MultiSetNX(context.Background(), redistypes.SingleConn(conn), []strings.Value{a}, []interface{}{1})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = strings.MultiSetNX(context.Background(), redistypes.SingleConn(conn), []strings.Value{a, b}, []interface{}{2, 3})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	value, err := b.Get()
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+}
+
+func TestMain(m *testing.M) {
+	netConn, err := net.Dial("tcp", internal.GetHostAndPort())
+	if err != nil {
+		fmt.Printf("Error opening net connection, err: %v", err)
+		os.Exit(1)
+	}
+
+	conn = redis.NewConn(netConn, time.Second, time.Second)
+	defer conn.Close()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,126 @@
+package redistypes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrTransactionAborted is returned when a transaction is not executed because one
+// of the keys it watched was modified.
+var ErrTransactionAborted = errors.New("Transaction aborted because a watched key was modified")
+
+// Transaction is a Batch that runs all of its commands atomically, using the Redis
+// commands MULTI and EXEC. The commands of several types can be queued in the same
+// Transaction, for example with list.NewPipelined and set.NewPipelined.
+//
+// See https://redis.io/topics/transactions.
+type Transaction struct {
+	provider ConnProvider
+	queue    commandQueue
+}
+
+// NewTransaction creates a Transaction that borrows a connection from p each time it
+// is executed.
+func NewTransaction(p ConnProvider) *Transaction {
+	return &Transaction{
+		provider: p,
+	}
+}
+
+// Queue queues the command cmd with args and returns a Future for its reply.
+func (t *Transaction) Queue(cmd string, args ...interface{}) *Future {
+	return t.queue.queue(cmd, args)
+}
+
+// Len returns the number of commands queued in the Transaction.
+func (t *Transaction) Len() int {
+	return len(t.queue.commands)
+}
+
+// Exec runs the queued commands in a transaction and resolves their futures. An error
+// returned by Redis for a single command while it runs is reported by its Future and
+// doesn't stop the other commands, as described in the Redis documentation.
+//
+// If Redis refuses to queue a command, for example because it has the wrong number of
+// arguments, none of the commands are run and Exec returns the error. If the
+// Transaction is run on a connection that is watching keys and one of them was
+// modified, Exec returns ErrTransactionAborted. In these cases, and if the commands
+// could not be sent or their replies could not be read, the futures are resolved to
+// the error returned by Exec.
+//
+// After Exec, the Transaction is empty and can be used again.
+func (t *Transaction) Exec(ctx context.Context) error {
+	commands := t.queue.take()
+
+	err := t.exec(ctx, commands)
+	if err != nil {
+		failUnresolved(commands, err)
+	}
+	return err
+}
+
+func (t *Transaction) exec(ctx context.Context, commands []queuedCommand) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	conn, err := internal.GetConn(ctx, t.provider)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err = conn.Send("MULTI"); err != nil {
+		return err
+	}
+	for _, command := range commands {
+		if err = conn.Send(command.name, command.args...); err != nil {
+			return err
+		}
+	}
+	if err = conn.Send("EXEC"); err != nil {
+		return err
+	}
+	if err = conn.Flush(); err != nil {
+		return err
+	}
+
+	// Read the reply to MULTI and the QUEUED replies. An error reply means Redis
+	// refused to queue the command, which makes EXEC fail.
+	var queueErr error
+	for i := -1; i < len(commands); i++ {
+		_, err := internal.ReceiveContext(ctx, conn)
+		if _, ok := err.(redis.Error); err != nil && !ok {
+			return err
+		} else if err != nil && i >= 0 {
+			if queueErr == nil {
+				queueErr = err
+			}
+			commands[i].future.resolve(nil, err)
+		}
+	}
+
+	replies, err := redis.Values(internal.ReceiveContext(ctx, conn))
+	if err == redis.ErrNil {
+		return ErrTransactionAborted
+	} else if queueErr != nil {
+		return queueErr
+	} else if err != nil {
+		return err
+	} else if len(replies) != len(commands) {
+		return errors.New("Unexpected response length")
+	}
+
+	for i, command := range commands {
+		if replyErr, ok := replies[i].(redis.Error); ok {
+			command.future.resolve(nil, replyErr)
+		} else {
+			command.future.resolve(replies[i], nil)
+		}
+	}
+
+	return nil
+}
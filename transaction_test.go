@@ -0,0 +1,116 @@
+package redistypes_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_Exec(t *testing.T) {
+	tx := redistypes.NewTransaction(redistypes.SingleConn(conn))
+
+	t.Run("empty transaction", func(t *testing.T) {
+		err := tx.Exec(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("several commands", func(t *testing.T) {
+		r := redistypes.NewRedisType(conn, test.RandomKey())
+		defer r.Delete()
+
+		set := tx.Queue("SET", r.Name(), 1)
+		incr := tx.Queue("INCR", r.Name())
+		expire := redistypes.NewPipelinedType(tx, r).Expire(10 * time.Second)
+		assert.Equal(t, 3, tx.Len())
+
+		err := tx.Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, tx.Len())
+
+		status, err := redis.String(set.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", status)
+
+		value, err := redis.Int(incr.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, value)
+
+		success, err := expire.Result()
+		assert.Nil(t, err)
+		assert.True(t, success)
+	})
+
+	t.Run("error while running", func(t *testing.T) {
+		name := test.RandomKey()
+		defer test.DeleteKey(name, conn)
+
+		push := tx.Queue("RPUSH", name, 1)
+		incr := tx.Queue("INCR", name)
+		length := tx.Queue("LLEN", name)
+
+		err := tx.Exec(context.Background())
+		assert.Nil(t, err)
+
+		_, err = push.Reply()
+		assert.Nil(t, err)
+
+		_, err = incr.Reply()
+		assert.NotNil(t, err)
+
+		value, err := redis.Int(length.Reply())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("error while queueing", func(t *testing.T) {
+		name := test.RandomKey()
+		defer test.DeleteKey(name, conn)
+
+		set := tx.Queue("SET", name, 1)
+		invalid := tx.Queue("INCR")
+
+		err := tx.Exec(context.Background())
+		assert.NotNil(t, err)
+
+		_, err = set.Reply()
+		assert.NotNil(t, err)
+
+		_, err = invalid.Reply()
+		assert.NotNil(t, err)
+
+		value, err := conn.Do("GET", name)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("watched key modified", func(t *testing.T) {
+		name := test.RandomKey()
+		defer test.DeleteKey(name, conn)
+
+		netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+		conn2 := redis.NewConn(netConn, time.Second, time.Second)
+		defer conn2.Close()
+
+		_, _ = conn2.Do("WATCH", name)
+		_, _ = conn.Do("SET", name, 1)
+
+		tx2 := redistypes.NewTransaction(redistypes.SingleConn(conn2))
+		set := tx2.Queue("SET", name, 2)
+
+		err := tx2.Exec(context.Background())
+		assert.Equal(t, redistypes.ErrTransactionAborted, err)
+
+		_, err = set.Reply()
+		assert.Equal(t, redistypes.ErrTransactionAborted, err)
+
+		value, _ := conn.Do("GET", name)
+		test.AssertEqual(t, 1, value)
+	})
+}
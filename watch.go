@@ -0,0 +1,90 @@
+package redistypes
+
+import (
+	"context"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes/internal"
+)
+
+const (
+	// watchMinBackoff is how long Watch waits before its first retry.
+	watchMinBackoff = time.Millisecond
+
+	// watchMaxBackoff is the longest Watch waits between retries.
+	watchMaxBackoff = 256 * time.Millisecond
+
+	// watchMaxAttempts is how many times Watch calls fn before it gives up.
+	watchMaxAttempts = 20
+)
+
+// Watch implements optimistic locking using the Redis command WATCH. It borrows a
+// connection from p, watches keys on it and calls fn with a Transaction that runs
+// on the same connection. WATCH only covers the commands of the connection it was sent
+// on, and a Type doesn't expose the provider it was created with, so the connection
+// has to come from p rather than from keys.
+//
+// fn reads the values it needs through the usual methods of the types, then queues
+// its changes in the Transaction, for example with list.NewPipelined. If fn returns
+// an error, nothing is run and Watch returns the error. Otherwise the Transaction is
+// executed. If one of the keys was modified after it was watched, the Transaction is
+// aborted and Watch calls fn again after a short backoff, which doubles with every
+// attempt. Since fn may be called several times, it should not have side effects
+// other than queueing commands, and it should only use the futures from the last call.
+//
+// Watch retries until the Transaction succeeds or ctx is done, at most 20 times in all.
+// If the keys are still being modified after that, it returns ErrTransactionAborted.
+//
+// See https://redis.io/topics/transactions#optimistic-locking-using-check-and-set.
+func Watch(ctx context.Context, p ConnProvider, fn func(tx *Transaction) error, keys ...Type) error {
+	names := make([]interface{}, len(keys))
+	for i, key := range keys {
+		names[i] = key.Name()
+	}
+
+	backoff := watchMinBackoff
+	for attempt := 1; ; attempt++ {
+		err := watchOnce(ctx, p, fn, names)
+		if err != ErrTransactionAborted || attempt == watchMaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > watchMaxBackoff {
+			backoff = watchMaxBackoff
+		}
+	}
+}
+
+// watchOnce makes a single attempt at watching names, calling fn and executing the
+// Transaction.
+func watchOnce(ctx context.Context, p ConnProvider, fn func(tx *Transaction) error, names []interface{}) error {
+	conn, err := internal.GetConn(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if len(names) > 0 {
+		if _, err = internal.DoContext(ctx, conn, "WATCH", names...); err != nil {
+			return err
+		}
+	}
+
+	tx := NewTransaction(SingleConn(conn))
+	if err = fn(tx); err != nil || tx.Len() == 0 {
+		// EXEC isn't sent, so the keys have to be unwatched explicitly. If that fails
+		// the connection is broken, and won't be reused anyway.
+		_, _ = conn.Do("UNWATCH")
+		return err
+	}
+
+	return tx.Exec(ctx)
+}
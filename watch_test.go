@@ -0,0 +1,158 @@
+package redistypes_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MasterOfBinary/redistypes"
+	"github.com/MasterOfBinary/redistypes/internal"
+	"github.com/MasterOfBinary/redistypes/internal/test"
+	"github.com/garyburd/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	p := redistypes.SingleConn(conn)
+
+	t.Run("check and set", func(t *testing.T) {
+		r := redistypes.NewRedisType(conn, test.RandomKey())
+		defer r.Delete()
+
+		_, _ = conn.Do("SET", r.Name(), 1)
+
+		calls := 0
+		err := redistypes.Watch(context.Background(), p, func(tx *redistypes.Transaction) error {
+			calls++
+			value, err := redis.Int(conn.Do("GET", r.Name()))
+			if err != nil {
+				return err
+			}
+			tx.Queue("SET", r.Name(), value*2)
+			return nil
+		}, r)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, calls)
+
+		value, _ := conn.Do("GET", r.Name())
+		test.AssertEqual(t, 2, value)
+	})
+
+	t.Run("error from fn", func(t *testing.T) {
+		r := redistypes.NewRedisType(conn, test.RandomKey())
+		defer r.Delete()
+
+		_, _ = conn.Do("SET", r.Name(), 1)
+
+		wantErr := errors.New("error from fn")
+		err := redistypes.Watch(context.Background(), p, func(tx *redistypes.Transaction) error {
+			tx.Queue("SET", r.Name(), 2)
+			return wantErr
+		}, r)
+		assert.Equal(t, wantErr, err)
+
+		value, _ := conn.Do("GET", r.Name())
+		test.AssertEqual(t, 1, value)
+	})
+
+	t.Run("retry after modification", func(t *testing.T) {
+		r := redistypes.NewRedisType(conn, test.RandomKey())
+		defer r.Delete()
+
+		netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+		conn2 := redis.NewConn(netConn, time.Second, time.Second)
+		defer conn2.Close()
+
+		_, _ = conn.Do("SET", r.Name(), 1)
+
+		calls := 0
+		err := redistypes.Watch(context.Background(), p, func(tx *redistypes.Transaction) error {
+			calls++
+			value, err := redis.Int(conn.Do("GET", r.Name()))
+			if err != nil {
+				return err
+			}
+			if calls == 1 {
+				// Modify the key from another connection
+				_, _ = conn2.Do("INCR", r.Name())
+			}
+			tx.Queue("SET", r.Name(), value*10)
+			return nil
+		}, r)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, calls)
+
+		value, _ := conn.Do("GET", r.Name())
+		test.AssertEqual(t, 20, value)
+	})
+
+	t.Run("too many modifications", func(t *testing.T) {
+		r := redistypes.NewRedisType(conn, test.RandomKey())
+		defer r.Delete()
+
+		netConn, _ := net.Dial("tcp", internal.GetHostAndPort())
+		conn2 := redis.NewConn(netConn, time.Second, time.Second)
+		defer conn2.Close()
+
+		calls := 0
+		err := redistypes.Watch(context.Background(), p, func(tx *redistypes.Transaction) error {
+			calls++
+			// Modify the key from another connection every time
+			_, _ = conn2.Do("INCR", r.Name())
+			tx.Queue("SET", r.Name(), 0)
+			return nil
+		}, r)
+		assert.Equal(t, redistypes.ErrTransactionAborted, err)
+		assert.Equal(t, 20, calls)
+	})
+
+	t.Run("concurrent increments", func(t *testing.T) {
+		pool := &redis.Pool{
+			MaxIdle: 4,
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", internal.GetHostAndPort())
+			},
+		}
+		defer pool.Close()
+
+		r := redistypes.NewRedisTypeFromProvider(pool, test.RandomKey())
+		defer r.Delete()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := redistypes.Watch(context.Background(), pool, func(tx *redistypes.Transaction) error {
+					c := pool.Get()
+					defer c.Close()
+
+					value, err := redis.Int(c.Do("GET", r.Name()))
+					if err != nil && err != redis.ErrNil {
+						return err
+					}
+					tx.Queue("SET", r.Name(), value+1)
+					return nil
+				}, r)
+				assert.Nil(t, err)
+			}()
+		}
+		wg.Wait()
+
+		value, _ := conn.Do("GET", r.Name())
+		test.AssertEqual(t, 10, value)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := redistypes.Watch(ctx, p, func(tx *redistypes.Transaction) error {
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+	})
+}